@@ -0,0 +1,109 @@
+package judy
+
+// A BiMap is a one-to-one map of uint64 keys to uint64 values that can be looked up in both directions.
+// It is made of two JudyL arrays, a forward array of key to value and a reverse array of value to key, which are
+// always kept in sync: every key has at most one value and every value belongs to at most one key.
+// The default value of this struct is a valid empty BiMap.
+//
+//    m := BiMap{}
+//    defer m.Free()
+//
+//    m.Put(internalID, externalID)
+//    key, ok := m.GetByValue(externalID)
+//
+// NOTE: Like the Judy arrays it is made of, a BiMap allocates memory outside of the Go runtime. It is very
+// important that you call Free() on it after using it to prevent memory leaks.
+type BiMap struct {
+	forward JudyL
+	reverse JudyL
+}
+
+// Put maps key to value.
+// Returns true if successful, or false if value already belongs to a different key, in which case the BiMap is
+// not changed. If key was previously mapped to another value, that value is released.
+func (m *BiMap) Put(key, value uint64) bool {
+	if k, ok := m.reverse.Get(value); ok && k != key {
+		return false
+	}
+	m.ForcePut(key, value)
+	return true
+}
+
+// ForcePut maps key to value, first removing any existing mapping of key and any existing mapping to value, so
+// that it always succeeds while keeping the BiMap one-to-one.
+func (m *BiMap) ForcePut(key, value uint64) {
+	if v, ok := m.forward.Get(key); ok {
+		m.reverse.Delete(v)
+	}
+	if k, ok := m.reverse.Get(value); ok {
+		m.forward.Delete(k)
+	}
+	m.forward.Insert(key, value)
+	m.reverse.Insert(value, key)
+}
+
+// GetByKey returns the value that key is mapped to.
+//   returns (value, true) if the key was found
+//   returns (_, false) if the key was not found
+func (m *BiMap) GetByKey(key uint64) (uint64, bool) {
+	return m.forward.Get(key)
+}
+
+// GetByValue returns the key that is mapped to value.
+//   returns (key, true) if the value was found
+//   returns (_, false) if the value was not found
+func (m *BiMap) GetByValue(value uint64) (uint64, bool) {
+	return m.reverse.Get(value)
+}
+
+// DeleteByKey removes the mapping of key.
+// Returns true if successful. Returns false if key was not present.
+func (m *BiMap) DeleteByKey(key uint64) bool {
+	v, ok := m.forward.Get(key)
+	if !ok {
+		return false
+	}
+	m.forward.Delete(key)
+	m.reverse.Delete(v)
+	return true
+}
+
+// DeleteByValue removes the mapping to value.
+// Returns true if successful. Returns false if value was not present.
+func (m *BiMap) DeleteByValue(value uint64) bool {
+	k, ok := m.reverse.Get(value)
+	if !ok {
+		return false
+	}
+	m.reverse.Delete(value)
+	m.forward.Delete(k)
+	return true
+}
+
+// CountAll returns the number of key/value pairs in the BiMap.
+func (m *BiMap) CountAll() uint64 {
+	return m.forward.CountAll()
+}
+
+// Forward returns the JudyL array of key to value, for ordered traversal and range counts over keys.
+// It must not be modified directly, and is freed with the BiMap.
+func (m *BiMap) Forward() *JudyL {
+	return &m.forward
+}
+
+// Reverse returns the JudyL array of value to key, for ordered traversal and range counts over values.
+// It must not be modified directly, and is freed with the BiMap.
+func (m *BiMap) Reverse() *JudyL {
+	return &m.reverse
+}
+
+// Return the number of bytes of memory currently in use by both arrays of the BiMap.
+func (m *BiMap) MemoryUsed() uint64 {
+	return m.forward.MemoryUsed() + m.reverse.MemoryUsed()
+}
+
+// Free both arrays of the BiMap.
+// Return the number of bytes freed.
+func (m *BiMap) Free() uint64 {
+	return m.forward.Free() + m.reverse.Free()
+}
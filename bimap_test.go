@@ -0,0 +1,127 @@
+package judy
+
+import "testing"
+
+func TestEmptyBiMap(t *testing.T) {
+
+	m := BiMap{}
+	if r := m.Free(); r != 0 {
+		t.Errorf("Free should return 0, returned %v", r)
+	}
+}
+
+func TestBiMapPutGet(t *testing.T) {
+
+	m := BiMap{}
+	defer m.Free()
+
+	var i uint64
+	for i = 0; i < 100; i++ {
+		if !m.Put(i, i+1000) {
+			t.Errorf("Put(%v, %v) should succeed", i, i+1000)
+		}
+	}
+
+	if ct := m.CountAll(); ct != 100 {
+		t.Errorf("Count should be 100, was %v", ct)
+	}
+	if v, ok := m.GetByKey(42); !ok || v != 1042 {
+		t.Errorf("GetByKey(42) should be 1042,true was %v,%v", v, ok)
+	}
+	if k, ok := m.GetByValue(1042); !ok || k != 42 {
+		t.Errorf("GetByValue(1042) should be 42,true was %v,%v", k, ok)
+	}
+	if _, ok := m.GetByKey(1042); ok {
+		t.Error("GetByKey(1042) should not be found")
+	}
+	if _, ok := m.GetByValue(42); ok {
+		t.Error("GetByValue(42) should not be found")
+	}
+}
+
+func TestBiMapOneToOne(t *testing.T) {
+
+	m := BiMap{}
+	defer m.Free()
+
+	m.Put(1, 10)
+	m.Put(2, 20)
+
+	if m.Put(3, 10) {
+		t.Error("Put of a value owned by another key should fail")
+	}
+	if _, ok := m.GetByKey(3); ok {
+		t.Error("A failed Put should not change the BiMap")
+	}
+	if !m.Put(1, 10) {
+		t.Error("Put of an existing pair should succeed")
+	}
+
+	// remapping a key releases its old value
+	if !m.Put(1, 11) {
+		t.Error("Put(1, 11) should succeed")
+	}
+	if _, ok := m.GetByValue(10); ok {
+		t.Error("Value 10 should have been released")
+	}
+	if k, _ := m.GetByValue(11); k != 1 {
+		t.Errorf("GetByValue(11) should be 1, was %v", k)
+	}
+
+	// ForcePut steals the value from its key
+	m.ForcePut(3, 20)
+	if _, ok := m.GetByKey(2); ok {
+		t.Error("Key 2 should have lost its value")
+	}
+	if k, _ := m.GetByValue(20); k != 3 {
+		t.Errorf("GetByValue(20) should be 3, was %v", k)
+	}
+	if ct, rct := m.Forward().CountAll(), m.Reverse().CountAll(); ct != 2 || rct != 2 {
+		t.Errorf("Both arrays should hold 2 pairs, were %v and %v", ct, rct)
+	}
+}
+
+func TestBiMapDelete(t *testing.T) {
+
+	m := BiMap{}
+	defer m.Free()
+
+	m.Put(1, 10)
+	m.Put(2, 20)
+
+	if !m.DeleteByKey(1) {
+		t.Error("DeleteByKey(1) should succeed")
+	}
+	if m.DeleteByKey(1) {
+		t.Error("DeleteByKey(1) should fail the second time")
+	}
+	if _, ok := m.GetByValue(10); ok {
+		t.Error("Value 10 should be removed with key 1")
+	}
+	if !m.DeleteByValue(20) {
+		t.Error("DeleteByValue(20) should succeed")
+	}
+	if _, ok := m.GetByKey(2); ok {
+		t.Error("Key 2 should be removed with value 20")
+	}
+	if ct := m.CountAll(); ct != 0 {
+		t.Errorf("Count should be 0, was %v", ct)
+	}
+	if mem := m.MemoryUsed(); mem != 0 {
+		t.Errorf("An empty BiMap should use no memory, used %v", mem)
+	}
+}
+
+func TestBiMapFree(t *testing.T) {
+
+	m := BiMap{}
+	m.Put(1, 10)
+	m.Put(2, 20)
+
+	if r := m.Free(); r == 0 {
+		t.Error("Free should return the bytes freed")
+	}
+	if ct, rct := m.Forward().CountAll(), m.Reverse().CountAll(); ct != 0 || rct != 0 {
+		t.Errorf("Both arrays should be empty after Free, counts were %v and %v", ct, rct)
+	}
+}
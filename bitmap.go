@@ -0,0 +1,127 @@
+package judy
+
+import "slices"
+
+// A BitmapIndex indexes one column of an in-memory table: for every distinct value in the column it keeps a Judy1
+// bitmap of the IDs of the rows holding that value, stored in a JudyL from value to bitmap. Each row has at most
+// one value. The default value of this struct is a valid empty index.
+//
+//    b := BitmapIndex{}
+//    defer b.Free()
+//
+//    b.Set(row, value)
+//    n := judy.Count(b.In(3, 5, 8))
+//    for row := range judy.Indexes(judy.And(b.Range(10, 20), judy.Not(other.Equal(0)))) {
+//        ...
+//    }
+//
+// The predicates Equal, In and Range return set expressions (see Expr), so they can be combined with the predicates
+// of other columns and evaluated with Indexes, Count and Any. Values are ordered as uint64 for Range; encode signed,
+// floating-point or time values with a KeyCodec to keep their natural order. A predicate reads the bitmaps in place,
+// so it is only valid until the index is next modified.
+//
+// NOTE: The Judy arrays allocate memory outside of the Go runtime. It is very important that you call Free() on a
+// BitmapIndex after using it to prevent memory leaks.
+type BitmapIndex struct {
+	bitmaps JudyLOfJudy1
+	values  JudyL
+}
+
+// Set the value of row, moving it from the bitmap of its previous value if it had one.
+func (b *BitmapIndex) Set(row, value uint64) {
+	if old, ok := b.values.Get(row); ok {
+		if old == value {
+			return
+		}
+		b.unset(row, old)
+	}
+	b.values.Insert(row, value)
+	b.bitmaps.GetOrCreate(value).Set(row)
+}
+
+// Remove row from the index. A value whose bitmap becomes empty is removed.
+// Returns true if successful. Returns false if row was not present.
+func (b *BitmapIndex) Remove(row uint64) bool {
+	value, ok := b.values.Get(row)
+	if !ok {
+		return false
+	}
+	b.unset(row, value)
+	return b.values.Delete(row)
+}
+
+func (b *BitmapIndex) unset(row, value uint64) {
+	bitmap := b.bitmaps.Get(value)
+	bitmap.Unset(row)
+	if bitmap.array == nil {
+		b.bitmaps.Delete(value)
+	}
+}
+
+// Get the value of row
+//   returns (value, true) if the row was found
+//   returns (_, false) if the row was not found
+func (b *BitmapIndex) Value(row uint64) (uint64, bool) {
+	return b.values.Get(row)
+}
+
+// Count the number of rows in the index.
+func (b *BitmapIndex) CountAll() uint64 {
+	return b.values.CountAll()
+}
+
+// Count the number of distinct values in the index.
+func (b *BitmapIndex) Cardinality() uint64 {
+	return b.bitmaps.CountAll()
+}
+
+// Equal returns the rows whose value is value.
+func (b *BitmapIndex) Equal(value uint64) Expr {
+	if bitmap := b.bitmaps.Get(value); bitmap != nil {
+		return bitmap
+	}
+	return Or()
+}
+
+// In returns the rows whose value is any of values. Repeated values are only counted once.
+func (b *BitmapIndex) In(values ...uint64) Expr {
+	// the bitmaps of a union must be distinct for it to be counted by adding up their counts
+	values = slices.Clone(values)
+	slices.Sort(values)
+	var xs []Expr
+	for _, value := range slices.Compact(values) {
+		if bitmap := b.bitmaps.Get(value); bitmap != nil {
+			xs = append(xs, bitmap)
+		}
+	}
+	return b.union(xs)
+}
+
+// Range returns the rows whose value is between lo and hi (inclusive).
+func (b *BitmapIndex) Range(lo, hi uint64) Expr {
+	var xs []Expr
+	for value, bitmap, ok := b.bitmaps.First(lo); ok && value <= hi; value, bitmap, ok = b.bitmaps.Next(value) {
+		xs = append(xs, bitmap)
+	}
+	return b.union(xs)
+}
+
+// union returns the union of the bitmaps xs. A row is in only one bitmap, so the union is counted by adding up the
+// counts of the bitmaps.
+func (b *BitmapIndex) union(xs []Expr) Expr {
+	if len(xs) == 1 {
+		return xs[0]
+	}
+	return &disjointOrExpr{orExpr{xs: xs}}
+}
+
+// Return the number of bytes of memory currently in use by the index.
+func (b *BitmapIndex) MemoryUsed() uint64 {
+	return b.bitmaps.MemoryUsed() + b.values.MemoryUsed()
+}
+
+// Free the entire index, including every bitmap.
+// Return the number of bytes freed.
+func (b *BitmapIndex) Free() uint64 {
+	return b.bitmaps.Free() + b.values.Free()
+}
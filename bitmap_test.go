@@ -0,0 +1,112 @@
+package judy
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestEmptyBitmapIndex(t *testing.T) {
+
+	b := BitmapIndex{}
+	if r := b.Free(); r != 0 {
+		t.Errorf("Free should return 0, returned %v", r)
+	}
+	if Any(b.Equal(1)) || Any(b.In(1, 2)) || Any(b.Range(0, 100)) {
+		t.Error("Predicates on an empty index should match nothing")
+	}
+	if b.Remove(1) {
+		t.Error("Remove on an empty index should return false")
+	}
+}
+
+func TestBitmapIndexSet(t *testing.T) {
+
+	b := BitmapIndex{}
+	defer b.Free()
+
+	b.Set(1, 10)
+	b.Set(2, 10)
+	b.Set(3, 20)
+
+	if ct := b.CountAll(); ct != 3 {
+		t.Errorf("CountAll should be 3, was %v", ct)
+	}
+	if ct := b.Cardinality(); ct != 2 {
+		t.Errorf("Cardinality should be 2, was %v", ct)
+	}
+
+	// moving the only row with value 20 removes its bitmap
+	b.Set(3, 10)
+	if v, ok := b.Value(3); !ok || v != 10 {
+		t.Errorf("Value(3) should be 10, was %v,%v", v, ok)
+	}
+	if ct := b.Cardinality(); ct != 1 {
+		t.Errorf("Cardinality should be 1 after moving row 3, was %v", ct)
+	}
+	if got := slices.Collect(Indexes(b.Equal(10))); !slices.Equal(got, []uint64{1, 2, 3}) {
+		t.Errorf("Equal(10) should be [1 2 3], was %v", got)
+	}
+
+	if !b.Remove(2) || b.Remove(2) {
+		t.Error("Remove(2) should succeed once")
+	}
+	if _, ok := b.Value(2); ok {
+		t.Error("Value(2) should not be found after Remove")
+	}
+	if ct := Count(b.Equal(10)); ct != 2 {
+		t.Errorf("Count(Equal(10)) should be 2, was %v", ct)
+	}
+	if b.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
+
+func TestBitmapIndexPredicates(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(1))
+	const rows = 5000
+
+	b, c := BitmapIndex{}, BitmapIndex{}
+	defer b.Free()
+	defer c.Free()
+
+	bv, cv := make([]uint64, rows), make([]uint64, rows)
+	for row := range bv {
+		bv[row], cv[row] = uint64(rng.Intn(50)), uint64(rng.Intn(3))
+		b.Set(uint64(row), bv[row])
+		c.Set(uint64(row), cv[row])
+	}
+
+	tests := []struct {
+		name  string
+		e     Expr
+		match func(row int) bool
+	}{
+		{"equal", b.Equal(7), func(row int) bool { return bv[row] == 7 }},
+		{"equal missing", b.Equal(50), func(row int) bool { return false }},
+		{"in", b.In(3, 49, 50, 12), func(row int) bool { return bv[row] == 3 || bv[row] == 12 || bv[row] == 49 }},
+		{"in repeated", b.In(3, 3), func(row int) bool { return bv[row] == 3 }},
+		{"in repeated mixed", b.In(3, 3, 12, 3), func(row int) bool { return bv[row] == 3 || bv[row] == 12 }},
+		{"range", b.Range(10, 19), func(row int) bool { return bv[row] >= 10 && bv[row] <= 19 }},
+		{"range single", b.Range(5, 5), func(row int) bool { return bv[row] == 5 }},
+		{"range empty", b.Range(60, 100), func(row int) bool { return false }},
+		{"and", And(b.Range(0, 24), c.Equal(1)), func(row int) bool { return bv[row] <= 24 && cv[row] == 1 }},
+		{"and not", And(b.In(1, 2, 3), Not(c.In(0, 2))), func(row int) bool { return bv[row] >= 1 && bv[row] <= 3 && cv[row] == 1 }},
+	}
+
+	for _, tt := range tests {
+		var expected []uint64
+		for row := 0; row < rows; row++ {
+			if tt.match(row) {
+				expected = append(expected, uint64(row))
+			}
+		}
+		if got := slices.Collect(Indexes(tt.e)); !slices.Equal(got, expected) {
+			t.Errorf("%v: returned %v rows, expected %v", tt.name, len(got), len(expected))
+		}
+		if ct := Count(tt.e); ct != uint64(len(expected)) {
+			t.Errorf("%v: count should be %v, was %v", tt.name, len(expected), ct)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package judy
+
+// A CachePolicy chooses which entry an LRUCache evicts when it is full.
+type CachePolicy int
+
+const (
+	LRU CachePolicy = iota // evict the least recently used entry
+	LFU                    // evict the least frequently used entry, the least recently used of those on a tie
+)
+
+// An LRUCache is a fixed-capacity cache of uint64 keys and values that evicts by recency (LRU) or by frequency
+// (LFU). It is made for caches of millions of keys, where a Go map and list spend GC time on their buckets and
+// nodes: the keys are indexed in a JudyL from key to slot, the entries are kept in a slice of slots with no
+// pointers for the GC to scan, and the access order is a JudyL of JudyL arrays from use count to access sequence
+// number to slot, so the entry to evict is found with two First(0) calls. In LRU mode every use count is 0.
+//
+//    c := judy.NewLRUCache(judy.LRU, 1_000_000, 0)
+//    defer c.Free()
+//
+//    c.OnEvict = func(key, value, size uint64) { release(value) }
+//    c.Put(key, value, 1)
+//    if value, ok := c.Get(key); ok {
+//        ...
+//    }
+//
+// The capacity is a maximum number of entries, a maximum total size of the entries, or both. The size of an entry
+// is whatever the caller passes to Put, such as the length of the data its value refers to.
+// In LFU mode the use counts never decay, so an entry that was used often long ago stays cached over newer ones.
+//
+// NOTE: The Judy arrays allocate memory outside of the Go runtime. It is very important that you call Free() on an
+// LRUCache after using it to prevent memory leaks.
+type LRUCache struct {
+	// OnEvict, if not nil, is called with each entry evicted to make room for another. It is not called for
+	// entries removed by Delete or Free, and it must not modify the cache.
+	OnEvict func(key, value, size uint64)
+
+	policy     CachePolicy
+	maxEntries uint64
+	maxBytes   uint64
+
+	slots   JudyL
+	order   JudyLOfJudyL
+	entries []cacheEntry
+	free    []uint64 // slots of entries that are not in use
+	bytes   uint64
+	seq     uint64
+}
+
+type cacheEntry struct {
+	key, value, size uint64
+	uses, seq        uint64 // the position of the entry in the access order
+}
+
+// NewLRUCache returns an empty cache with the given eviction policy that holds at most maxEntries entries with a
+// total size of at most maxBytes; a limit of 0 means no limit.
+// It panics if both limits are 0.
+func NewLRUCache(policy CachePolicy, maxEntries, maxBytes uint64) *LRUCache {
+	if maxEntries == 0 && maxBytes == 0 {
+		panic("judy: LRUCache needs a maximum number of entries or bytes")
+	}
+	return &LRUCache{policy: policy, maxEntries: maxEntries, maxBytes: maxBytes}
+}
+
+// Put sets the value and size of key, counting as a use of it, and evicts entries until the cache is within its
+// capacity. The entry being put is never evicted by its own Put.
+// Return true if the entry was put, otherwise false, leaving the cache unchanged, if size alone is over the
+// maximum number of bytes.
+func (c *LRUCache) Put(key, value, size uint64) bool {
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return false
+	}
+	var uses uint64
+	if slot, ok := c.slots.Get(key); ok {
+		uses = c.entries[slot].uses
+		c.remove(slot)
+	}
+	for (c.maxEntries > 0 && c.CountAll() >= c.maxEntries) || (c.maxBytes > 0 && c.bytes+size > c.maxBytes) {
+		c.evict()
+	}
+
+	var slot uint64
+	if n := len(c.free); n > 0 {
+		slot, c.free = c.free[n-1], c.free[:n-1]
+	} else {
+		slot = uint64(len(c.entries))
+		c.entries = append(c.entries, cacheEntry{})
+	}
+	c.entries[slot] = cacheEntry{key: key, value: value, size: size, uses: uses}
+	c.slots.Insert(key, slot)
+	c.bytes += size
+	c.use(slot)
+	return true
+}
+
+// Get the value of key, counting as a use of it.
+//   returns (value, true) if key is cached
+//   returns (_, false) if key is not cached
+func (c *LRUCache) Get(key uint64) (uint64, bool) {
+	slot, ok := c.slots.Get(key)
+	if !ok {
+		return 0, false
+	}
+	c.unlink(slot)
+	c.use(slot)
+	return c.entries[slot].value, true
+}
+
+// Peek returns the value of key like Get, without counting as a use of it.
+//   returns (value, true) if key is cached
+//   returns (_, false) if key is not cached
+func (c *LRUCache) Peek(key uint64) (uint64, bool) {
+	slot, ok := c.slots.Get(key)
+	if !ok {
+		return 0, false
+	}
+	return c.entries[slot].value, true
+}
+
+// Delete key from the cache, without calling OnEvict.
+// Returns true if successful. Returns false if key was not cached.
+func (c *LRUCache) Delete(key uint64) bool {
+	slot, ok := c.slots.Get(key)
+	if !ok {
+		return false
+	}
+	c.remove(slot)
+	return true
+}
+
+// Count the number of entries in the cache.
+func (c *LRUCache) CountAll() uint64 {
+	return uint64(len(c.entries) - len(c.free))
+}
+
+// Return the total size of the entries in the cache, as passed to Put.
+func (c *LRUCache) Bytes() uint64 {
+	return c.bytes
+}
+
+// use moves the entry in slot, which must not be in the access order, to the end of the access order for its new
+// use count.
+func (c *LRUCache) use(slot uint64) {
+	e := &c.entries[slot]
+	if c.policy == LFU {
+		e.uses++
+	}
+	c.seq++
+	e.seq = c.seq
+	c.order.GetOrCreate(e.uses).Insert(e.seq, slot)
+}
+
+// unlink removes the entry in slot from the access order.
+func (c *LRUCache) unlink(slot uint64) {
+	e := &c.entries[slot]
+	seqs := c.order.Get(e.uses)
+	seqs.Delete(e.seq)
+	if seqs.array == nil {
+		c.order.Delete(e.uses)
+	}
+}
+
+// remove the entry in slot from the cache.
+func (c *LRUCache) remove(slot uint64) {
+	c.unlink(slot)
+	c.slots.Delete(c.entries[slot].key)
+	c.bytes -= c.entries[slot].size
+	c.free = append(c.free, slot)
+}
+
+// evict the first entry in the access order and pass it to OnEvict.
+func (c *LRUCache) evict() {
+	_, seqs, _ := c.order.First(0)
+	_, slot, _ := seqs.First(0)
+	e := c.entries[slot]
+	c.remove(slot)
+	if c.OnEvict != nil {
+		c.OnEvict(e.key, e.value, e.size)
+	}
+}
+
+// Return the number of bytes of memory currently in use by the Judy arrays of the cache. The slice of entries is
+// not included.
+func (c *LRUCache) MemoryUsed() uint64 {
+	return c.slots.MemoryUsed() + c.order.MemoryUsed()
+}
+
+// Free every entry of the cache, without calling OnEvict.
+// Return the number of bytes of Judy array memory freed.
+func (c *LRUCache) Free() uint64 {
+	freed := c.slots.Free() + c.order.Free()
+	c.entries, c.free = nil, nil
+	c.bytes, c.seq = 0, 0
+	return freed
+}
@@ -0,0 +1,135 @@
+package judy
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLRUCache(t *testing.T) {
+
+	c := NewLRUCache(LRU, 3, 0)
+	defer c.Free()
+
+	var evicted []uint64
+	c.OnEvict = func(key, value, size uint64) {
+		if value != key*10 {
+			t.Errorf("OnEvict for %v should get value %v, got %v", key, key*10, value)
+		}
+		evicted = append(evicted, key)
+	}
+
+	for key := uint64(1); key <= 3; key++ {
+		c.Put(key, key*10, 1)
+	}
+	if v, ok := c.Get(1); !ok || v != 10 {
+		t.Errorf("Get(1) should be 10, was %v,%v", v, ok)
+	}
+	if _, ok := c.Peek(2); !ok {
+		t.Error("Peek(2) should be found")
+	}
+	// 2 is the least recently used, as Peek does not count
+	c.Put(4, 40, 1)
+	if !slices.Equal(evicted, []uint64{2}) {
+		t.Errorf("Put(4) should evict 2, evicted %v", evicted)
+	}
+	// updating 3 makes it the most recently used
+	c.Put(3, 30, 1)
+	c.Put(5, 50, 1)
+	if !slices.Equal(evicted, []uint64{2, 1}) {
+		t.Errorf("Put(5) should evict 1, evicted %v", evicted)
+	}
+	if ct := c.CountAll(); ct != 3 {
+		t.Errorf("CountAll should be 3, was %v", ct)
+	}
+
+	if !c.Delete(4) || c.Delete(4) {
+		t.Error("Delete(4) should succeed once")
+	}
+	c.Put(6, 60, 1)
+	if len(evicted) != 2 {
+		t.Errorf("Put after Delete should not evict, evicted %v", evicted)
+	}
+	if c.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+	if r := c.Free(); r == 0 {
+		t.Error("Free should return the bytes freed")
+	}
+	if ct := c.CountAll(); ct != 0 {
+		t.Errorf("CountAll after Free should be 0, was %v", ct)
+	}
+}
+
+func TestLFUCache(t *testing.T) {
+
+	c := NewLRUCache(LFU, 3, 0)
+	defer c.Free()
+
+	var evicted []uint64
+	c.OnEvict = func(key, _, _ uint64) {
+		evicted = append(evicted, key)
+	}
+
+	c.Put(1, 0, 1)
+	c.Put(2, 0, 1)
+	c.Put(3, 0, 1)
+	c.Get(1)
+	c.Get(1)
+	c.Get(3)
+	c.Get(2)
+	// 1 has 3 uses, 3 and 2 have 2 each and 3 was used less recently
+	c.Put(4, 0, 1)
+	// updating 2 keeps its use count, so it has 3 uses to the 2 of 4
+	c.Put(2, 1, 1)
+	c.Get(4)
+	c.Put(5, 0, 1)
+	if !slices.Equal(evicted, []uint64{3, 4}) {
+		t.Errorf("The evictions should be [3 4], were %v", evicted)
+	}
+	if _, ok := c.Peek(1); !ok {
+		t.Error("The most frequently used key should still be cached")
+	}
+}
+
+func TestLRUCacheBytes(t *testing.T) {
+
+	c := NewLRUCache(LRU, 0, 100)
+	defer c.Free()
+
+	var evicted []uint64
+	c.OnEvict = func(key, _, _ uint64) {
+		evicted = append(evicted, key)
+	}
+
+	c.Put(1, 0, 40)
+	c.Put(2, 0, 40)
+	c.Put(3, 0, 20)
+	if b := c.Bytes(); b != 100 {
+		t.Errorf("Bytes should be 100, was %v", b)
+	}
+	c.Put(4, 0, 50)
+	if !slices.Equal(evicted, []uint64{1, 2}) || c.Bytes() != 70 {
+		t.Errorf("Put(4) should evict 1 and 2 leaving 70 bytes, evicted %v leaving %v", evicted, c.Bytes())
+	}
+	// growing an entry evicts others, never itself
+	c.Put(4, 0, 100)
+	if !slices.Equal(evicted, []uint64{1, 2, 3}) || c.Bytes() != 100 || c.CountAll() != 1 {
+		t.Errorf("Growing 4 should evict 3, evicted %v", evicted)
+	}
+	if c.Put(5, 0, 101) {
+		t.Error("An entry larger than the cache should not be put")
+	}
+	if _, ok := c.Peek(4); !ok {
+		t.Error("A rejected Put should leave the cache unchanged")
+	}
+}
+
+func TestNewLRUCachePanics(t *testing.T) {
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewLRUCache without a capacity should panic")
+		}
+	}()
+	NewLRUCache(LRU, 0, 0)
+}
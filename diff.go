@@ -0,0 +1,92 @@
+package judy
+
+import "iter"
+
+// ChangeKind is the kind of a Change between two JudyL arrays.
+type ChangeKind int
+
+const (
+	// The index is present in the new array but not in the old one.
+	Added ChangeKind = iota
+	// The index is present in the old array but not in the new one.
+	Removed
+	// The index is present in both arrays with different values.
+	Modified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "Added"
+	case Removed:
+		return "Removed"
+	case Modified:
+		return "Modified"
+	}
+	return "ChangeKind(?)"
+}
+
+// A Change is a single difference between two JudyL arrays, as reported by Diff.
+// OldValue is only meaningful for Removed and Modified changes, and NewValue only for Added and Modified changes.
+type Change struct {
+	Kind     ChangeKind
+	Index    uint64
+	OldValue uint64
+	NewValue uint64
+}
+
+// Diff compares the JudyL arrays from and to and yields the changes that turn from into to, in ascending index
+// order. Indexes present in both arrays with the same value produce no change.
+//
+// Both arrays are walked together with First and Next, so the cost is a single pass over the two arrays. The
+// iterator reads the arrays lazily, so the changes can be applied to from while iterating, as in
+// ApplyChanges(&from, Diff(&from, &to)).
+//
+//    for c := range judy.Diff(&local, &fresh) {
+//        fmt.Println(c.Kind, c.Index)
+//    }
+func Diff(from, to *JudyL) iter.Seq[Change] {
+	return func(yield func(Change) bool) {
+		fi, fv, fok := from.First(0)
+		ti, tv, tok := to.First(0)
+
+		for fok || tok {
+			switch {
+			case !tok || (fok && fi < ti):
+				if !yield(Change{Kind: Removed, Index: fi, OldValue: fv}) {
+					return
+				}
+				fi, fv, fok = from.Next(fi)
+			case !fok || ti < fi:
+				if !yield(Change{Kind: Added, Index: ti, NewValue: tv}) {
+					return
+				}
+				ti, tv, tok = to.Next(ti)
+			default:
+				if fv != tv && !yield(Change{Kind: Modified, Index: fi, OldValue: fv, NewValue: tv}) {
+					return
+				}
+				fi, fv, fok = from.Next(fi)
+				ti, tv, tok = to.Next(ti)
+			}
+		}
+	}
+}
+
+// ApplyChanges replays changes, as produced by Diff, onto the JudyL array dst: Added and Modified changes insert
+// NewValue at Index, and Removed changes delete Index. It returns the number of changes applied.
+//
+// Applying Diff(from, to) to a copy of from makes it equal to to.
+func ApplyChanges(dst *JudyL, changes iter.Seq[Change]) int {
+	var n int
+	for c := range changes {
+		switch c.Kind {
+		case Added, Modified:
+			dst.Insert(c.Index, c.NewValue)
+		case Removed:
+			dst.Delete(c.Index)
+		}
+		n++
+	}
+	return n
+}
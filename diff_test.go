@@ -0,0 +1,107 @@
+package judy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+
+	from := JudyL{}
+	defer from.Free()
+	to := JudyL{}
+	defer to.Free()
+
+	from.Insert(0, 1)
+	from.Insert(10, 2)
+	from.Insert(20, 3)
+	from.Insert(math.MaxUint64, 4)
+
+	to.Insert(0, 1)
+	to.Insert(5, 9)
+	to.Insert(20, 30)
+	to.Insert(math.MaxUint64, 4)
+	to.Insert(math.MaxUint64-1, 8)
+
+	expected := []Change{
+		{Kind: Added, Index: 5, NewValue: 9},
+		{Kind: Removed, Index: 10, OldValue: 2},
+		{Kind: Modified, Index: 20, OldValue: 3, NewValue: 30},
+		{Kind: Added, Index: math.MaxUint64 - 1, NewValue: 8},
+	}
+
+	var changes []Change
+	for c := range Diff(&from, &to) {
+		changes = append(changes, c)
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Diff should return %v changes, returned %v", expected, changes)
+	}
+	for n := range expected {
+		if changes[n] != expected[n] {
+			t.Errorf("Change %v should be %+v, was %+v", n, expected[n], changes[n])
+		}
+	}
+
+	for range Diff(&to, &to) {
+		t.Error("Diff of an array with itself should be empty")
+	}
+
+	empty := JudyL{}
+	var removed int
+	for c := range Diff(&from, &empty) {
+		if c.Kind != Removed {
+			t.Errorf("Diff to an empty array should only remove, was %+v", c)
+		}
+		removed++
+	}
+	if removed != 4 {
+		t.Errorf("Diff to an empty array should remove 4 indexes, removed %v", removed)
+	}
+
+	for c := range Diff(&from, &to) {
+		if c.Index != 5 {
+			t.Errorf("Iteration should stop after the first change, got %+v", c)
+		}
+		break
+	}
+}
+
+func TestApplyChanges(t *testing.T) {
+
+	from := JudyL{}
+	defer from.Free()
+	to := JudyL{}
+	defer to.Free()
+	dst := JudyL{}
+	defer dst.Free()
+
+	var i uint64
+	for i = 0; i < 100; i++ {
+		from.Insert(i*2, i)
+		dst.Insert(i*2, i)
+		if i%3 != 0 {
+			to.Insert(i*3, i+i%2)
+		}
+	}
+
+	if n := ApplyChanges(&dst, Diff(&from, &to)); n == 0 {
+		t.Error("ApplyChanges should apply some changes")
+	}
+	for range Diff(&dst, &to) {
+		t.Fatal("Applying Diff(from, to) to a copy of from should make it equal to to")
+	}
+
+	// apply the changes to from while the diff is still reading it
+	ApplyChanges(&from, Diff(&from, &to))
+	for range Diff(&from, &to) {
+		t.Fatal("Applying Diff(from, to) to from should make it equal to to")
+	}
+}
+
+func TestChangeKindString(t *testing.T) {
+
+	if s := Modified.String(); s != "Modified" {
+		t.Errorf("Modified.String() should be Modified, was %v", s)
+	}
+}
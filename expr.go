@@ -0,0 +1,253 @@
+package judy
+
+import (
+	"cmp"
+	"iter"
+	"math"
+	"slices"
+)
+
+// An Expr is a set of indexes that can be searched in ascending order. A *Judy1 is an Expr, and And, Or, Not and
+// Xor combine Exprs into set expressions such as (A ∪ B) ∩ ¬C:
+//
+//    e := judy.And(judy.Or(&a, &b), judy.Not(&c))
+//    for idx := range judy.Indexes(e) {
+//        fmt.Println(idx)
+//    }
+//
+// Expressions are evaluated lazily, by seeking each operand with First (leapfrogging over the operands of And),
+// so no intermediate Judy1 arrays are materialized and Any stops at the first match. An expression reads its
+// arrays as it is evaluated, so the arrays must not be freed while it is in use.
+type Expr interface {
+	// Search (inclusive) for the first index in the set that is equal to or greater than the passed index.
+	First(index uint64) (uint64, bool)
+}
+
+// And returns the intersection of xs. Not operands are evaluated as exclusions, so And(a, Not(b)) is a minus b
+// and never iterates the complement of b. The remaining operands are leapfrogged starting from the one with the
+// smallest count (see Judy1.CountAll), which is ordered once when And is called.
+// And() with no operands is the empty set.
+func And(xs ...Expr) Expr {
+	e := &andExpr{}
+	for _, x := range xs {
+		if n, ok := x.(*notExpr); ok {
+			e.exclude = append(e.exclude, n.x)
+		} else {
+			e.include = append(e.include, x)
+		}
+	}
+	if len(e.include) == 0 {
+		if len(e.exclude) == 0 {
+			return &orExpr{}
+		}
+		return &notExpr{x: Or(e.exclude...)}
+	}
+	slices.SortStableFunc(e.include, func(a, b Expr) int {
+		return cmp.Compare(cost(a), cost(b))
+	})
+	return e
+}
+
+// Or returns the union of xs. Or() with no operands is the empty set.
+func Or(xs ...Expr) Expr {
+	return &orExpr{xs: xs}
+}
+
+// Xor returns the indexes that are in an odd number of xs, which for two operands is the symmetric difference.
+func Xor(xs ...Expr) Expr {
+	return &xorExpr{xs: xs}
+}
+
+// Not returns the complement of x over the whole uint64 index space. Use it as an operand of And, which
+// subtracts it; on its own the complement is nearly every index and should only be searched, not iterated.
+func Not(x Expr) Expr {
+	if n, ok := x.(*notExpr); ok {
+		return n.x
+	}
+	return &notExpr{x: x}
+}
+
+// Indexes returns an iterator over the indexes in x, in ascending order.
+func Indexes(x Expr) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for idx, ok := x.First(0); ok; idx, ok = x.First(idx + 1) {
+			if !yield(idx) || idx == math.MaxUint64 {
+				return
+			}
+		}
+	}
+}
+
+// Count returns the number of indexes in x. A plain *Judy1 is counted with CountAll and the complement of one with
+// Not is counted from it; anything else is counted by searching through it.
+// As with CountAll, a return value of 0 can also mean that all 2^64 indexes are present (only for the complement of
+// an empty set).
+func Count(x Expr) uint64 {
+	switch e := x.(type) {
+	case *Judy1:
+		return e.CountAll()
+	case *notExpr:
+		return -Count(e.x)
+	case *disjointOrExpr:
+		var n uint64
+		for _, x := range e.xs {
+			n += Count(x)
+		}
+		return n
+	}
+	var n uint64
+	for idx, ok := x.First(0); ok; idx, ok = x.First(idx + 1) {
+		n++
+		if idx == math.MaxUint64 {
+			break
+		}
+	}
+	return n
+}
+
+// Any reports whether x contains at least one index.
+func Any(x Expr) bool {
+	_, ok := x.First(0)
+	return ok
+}
+
+// cost returns an upper bound on the number of indexes in x, used to order the operands of And.
+func cost(x Expr) uint64 {
+	switch e := x.(type) {
+	case *Judy1:
+		return e.CountAll()
+	case *andExpr:
+		return cost(e.include[0])
+	case *orExpr:
+		return sumCost(e.xs)
+	case *disjointOrExpr:
+		return sumCost(e.xs)
+	case *xorExpr:
+		return sumCost(e.xs)
+	}
+	return math.MaxUint64
+}
+
+func sumCost(xs []Expr) uint64 {
+	var n uint64
+	for _, x := range xs {
+		c := cost(x)
+		if n += c; n < c {
+			return math.MaxUint64
+		}
+	}
+	return n
+}
+
+// andExpr intersects include by leapfrogging and skips indexes in any of exclude.
+type andExpr struct {
+	include []Expr
+	exclude []Expr
+}
+
+func (e *andExpr) First(index uint64) (uint64, bool) {
+	for {
+		// advance every operand to the same index
+		for agreed := 0; agreed < len(e.include); {
+			for _, x := range e.include {
+				idx, ok := x.First(index)
+				if !ok {
+					return 0, false
+				}
+				if idx == index {
+					agreed++
+				} else {
+					index, agreed = idx, 0
+					break
+				}
+			}
+		}
+
+		if !contains(e.exclude, index) {
+			return index, true
+		}
+		if index == math.MaxUint64 {
+			return 0, false
+		}
+		index++
+	}
+}
+
+type orExpr struct {
+	xs []Expr
+}
+
+func (e *orExpr) First(index uint64) (uint64, bool) {
+	var best uint64
+	found := false
+	for _, x := range e.xs {
+		if idx, ok := x.First(index); ok && (!found || idx < best) {
+			best, found = idx, true
+			if idx == index {
+				break
+			}
+		}
+	}
+	return best, found
+}
+
+// disjointOrExpr is an orExpr whose operands have no indexes in common, so it is counted by adding up the counts of
+// its operands.
+type disjointOrExpr struct {
+	orExpr
+}
+
+type xorExpr struct {
+	xs []Expr
+}
+
+func (e *xorExpr) First(index uint64) (uint64, bool) {
+	for {
+		var best uint64
+		found, odd := false, false
+		for _, x := range e.xs {
+			idx, ok := x.First(index)
+			switch {
+			case !ok || found && idx > best:
+			case !found || idx < best:
+				best, found, odd = idx, true, true
+			default:
+				odd = !odd
+			}
+		}
+		if !found || odd {
+			return best, found
+		}
+		if best == math.MaxUint64 {
+			return 0, false
+		}
+		index = best + 1
+	}
+}
+
+type notExpr struct {
+	x Expr
+}
+
+func (e *notExpr) First(index uint64) (uint64, bool) {
+	for {
+		idx, ok := e.x.First(index)
+		if !ok || idx != index {
+			return index, true
+		}
+		if index == math.MaxUint64 {
+			return 0, false
+		}
+		index++
+	}
+}
+
+// contains reports whether index is in any of xs.
+func contains(xs []Expr, index uint64) bool {
+	for _, x := range xs {
+		if idx, ok := x.First(index); ok && idx == index {
+			return true
+		}
+	}
+	return false
+}
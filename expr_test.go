@@ -0,0 +1,154 @@
+package judy
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func newExprTestSets(rng *rand.Rand, n int, max uint64) []*Judy1 {
+	sets := make([]*Judy1, n)
+	for s := range sets {
+		sets[s] = &Judy1{}
+		// sets of very different density, so that And reorders its operands
+		for i := uint64(0); i < max; i++ {
+			if rng.Intn(2<<s) == 0 {
+				sets[s].Set(i)
+			}
+		}
+	}
+	return sets
+}
+
+func TestExpr(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(1))
+	const max = 3000
+	sets := newExprTestSets(rng, 4, max)
+	for _, s := range sets {
+		defer s.Free()
+	}
+	a, b, c, d := sets[0], sets[1], sets[2], sets[3]
+
+	tests := []struct {
+		name  string
+		e     Expr
+		match func(i uint64) bool
+	}{
+		{"leaf", a, func(i uint64) bool { return a.Test(i) }},
+		{"and", And(a, b, c), func(i uint64) bool { return a.Test(i) && b.Test(i) && c.Test(i) }},
+		{"or", Or(c, d), func(i uint64) bool { return c.Test(i) || d.Test(i) }},
+		{"xor", Xor(a, b), func(i uint64) bool { return a.Test(i) != b.Test(i) }},
+		{"xor3", Xor(a, b, c), func(i uint64) bool { return a.Test(i) != b.Test(i) != c.Test(i) }},
+		{"union minus", And(Or(a, b), Not(c)), func(i uint64) bool { return (a.Test(i) || b.Test(i)) && !c.Test(i) }},
+		{"minus two", And(a, Not(b), Not(d)), func(i uint64) bool { return a.Test(i) && !b.Test(i) && !d.Test(i) }},
+		{"nested", Or(And(a, d), Xor(b, And(c, Not(a)))), func(i uint64) bool {
+			return a.Test(i) && d.Test(i) || b.Test(i) != (c.Test(i) && !a.Test(i))
+		}},
+		{"empty and", And(), func(i uint64) bool { return false }},
+		{"empty or", Or(), func(i uint64) bool { return false }},
+	}
+
+	for _, tt := range tests {
+		var expected []uint64
+		for i := uint64(0); i < max; i++ {
+			if tt.match(i) {
+				expected = append(expected, i)
+			}
+		}
+		if got := slices.Collect(Indexes(tt.e)); !slices.Equal(got, expected) {
+			t.Errorf("%v: returned %v indexes, expected %v", tt.name, len(got), len(expected))
+		}
+		if ct := Count(tt.e); ct != uint64(len(expected)) {
+			t.Errorf("%v: count should be %v, was %v", tt.name, len(expected), ct)
+		}
+		if any := Any(tt.e); any != (len(expected) > 0) {
+			t.Errorf("%v: Any should be %v", tt.name, len(expected) > 0)
+		}
+	}
+}
+
+func TestExprNot(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	j.Set(0)
+	j.Set(1)
+	j.Set(3)
+	j.Set(math.MaxUint64)
+
+	n := Not(&j)
+	if idx, ok := n.First(0); !ok || idx != 2 {
+		t.Errorf("Not.First(0) should be 2, was %v,%v", idx, ok)
+	}
+	if idx, ok := n.First(3); !ok || idx != 4 {
+		t.Errorf("Not.First(3) should be 4, was %v,%v", idx, ok)
+	}
+	if _, ok := n.First(math.MaxUint64); ok {
+		t.Error("Not.First(MaxUint64) should not be found")
+	}
+	if ct := Count(n); ct != math.MaxUint64-3 {
+		t.Errorf("Count of the complement should be 2^64-4, was %v", ct)
+	}
+	if Not(n) != Expr(&j) {
+		t.Error("Not(Not(x)) should be x")
+	}
+
+	empty := Judy1{}
+	if ct := Count(Not(&empty)); ct != 0 {
+		t.Errorf("Count of the full complement should wrap to 0, was %v", ct)
+	}
+	if ct := Count(And(Not(&j), Not(&empty))); ct != math.MaxUint64-3 {
+		t.Errorf("And of only Not operands should be the complement of their union, count was %v", ct)
+	}
+}
+
+func TestExprEdges(t *testing.T) {
+
+	a, b := Judy1{}, Judy1{}
+	defer a.Free()
+	defer b.Free()
+
+	a.Set(math.MaxUint64)
+	a.Set(5)
+	b.Set(math.MaxUint64)
+
+	if got := slices.Collect(Indexes(And(&a, &b))); !slices.Equal(got, []uint64{math.MaxUint64}) {
+		t.Errorf("And should find MaxUint64, was %v", got)
+	}
+	if got := slices.Collect(Indexes(Xor(&a, &b))); !slices.Equal(got, []uint64{5}) {
+		t.Errorf("Xor should be [5], was %v", got)
+	}
+	if got := slices.Collect(Indexes(And(&a, Not(&b)))); !slices.Equal(got, []uint64{5}) {
+		t.Errorf("a minus b should be [5], was %v", got)
+	}
+	if Any(And(&b, Not(&a))) {
+		t.Error("b minus a should be empty")
+	}
+	if ct := Count(Or(&a, &b)); ct != 2 {
+		t.Errorf("Count of the union should be 2, was %v", ct)
+	}
+}
+
+func TestExprIndexesBreak(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(2))
+	sets := newExprTestSets(rng, 3, 1000)
+	for _, s := range sets {
+		defer s.Free()
+	}
+	e := And(Or(sets[0], sets[1]), Not(sets[2]))
+
+	var got []uint64
+	for idx := range Indexes(e) {
+		if len(got) == 5 {
+			break
+		}
+		got = append(got, idx)
+	}
+	if expected := slices.Collect(Indexes(e))[:5]; !slices.Equal(got, expected) {
+		t.Errorf("Indexes should stop after 5 indexes %v, returned %v", expected, got)
+	}
+}
@@ -0,0 +1,194 @@
+package judy
+
+import (
+	"iter"
+	"math"
+)
+
+// An ExtentAllocator allocates contiguous extents of blocks, such as free space on a disk, with first-fit or
+// best-fit placement. The free extents are stored in a JudyL from start block to length, and indexed by length in a
+// JudyL of Judy1 arrays of start blocks, so a best fit is a single search. Releasing an extent coalesces it with
+// the free extents on either side.
+// The default value of this struct is a valid allocator with no free space; Release the free space to start with.
+//
+//    a := judy.ExtentAllocator{}
+//    defer a.Free()
+//
+//    a.Release(0, 1<<20) // 1M free blocks
+//    start, ok := a.AllocateBestFit(16)
+//    ...
+//    a.Release(start, 16)
+//
+// NOTE: The Judy arrays allocate memory outside of the Go runtime. It is very important that you call Free() on an
+// ExtentAllocator after using it to prevent memory leaks.
+type ExtentAllocator struct {
+	byStart JudyL
+	bySize  JudyLOfJudy1
+	free    uint64
+}
+
+// ExtentStats describes the free space of an ExtentAllocator.
+type ExtentStats struct {
+	FreeBlocks uint64 // total length of the free extents
+	Extents    uint64 // number of free extents
+	Largest    uint64 // length of the largest free extent
+	Smallest   uint64 // length of the smallest free extent
+
+	// Fragmentation is the fraction of the free blocks outside the largest free extent: 0 when the free space is
+	// one extent (or there is none), approaching 1 as it is split into many small extents.
+	Fragmentation float64
+}
+
+// AllocateFirstFit allocates n blocks at the start of the free extent with the lowest start that is at least n
+// blocks long. Only the extents of each length of at least n are compared, not every extent.
+//   returns (start, true) if the blocks start to start+n-1 were allocated
+//   returns (_, false) if no free extent is long enough, or n is 0
+func (a *ExtentAllocator) AllocateFirstFit(n uint64) (uint64, bool) {
+	if n == 0 {
+		return 0, false
+	}
+	var best, bestSize uint64
+	found := false
+	for size, starts, ok := a.bySize.First(n); ok; size, starts, ok = a.bySize.Next(size) {
+		if start, _ := starts.First(0); !found || start < best {
+			best, bestSize, found = start, size, true
+		}
+	}
+	if !found {
+		return 0, false
+	}
+	a.take(best, bestSize, n)
+	return best, true
+}
+
+// AllocateBestFit allocates n blocks at the start of the shortest free extent that is at least n blocks long, the
+// one with the lowest start if there are several.
+//   returns (start, true) if the blocks start to start+n-1 were allocated
+//   returns (_, false) if no free extent is long enough, or n is 0
+func (a *ExtentAllocator) AllocateBestFit(n uint64) (uint64, bool) {
+	if n == 0 {
+		return 0, false
+	}
+	size, starts, ok := a.bySize.First(n)
+	if !ok {
+		return 0, false
+	}
+	start, _ := starts.First(0)
+	a.take(start, size, n)
+	return start, true
+}
+
+// take allocates the first n blocks of the free extent start of length size.
+func (a *ExtentAllocator) take(start, size, n uint64) {
+	a.remove(start, size)
+	if size > n {
+		a.insert(start+n, size-n)
+	}
+	a.free -= n
+}
+
+// Release the n blocks from start to start+n-1, making them free, and coalesce them with the free extents that
+// end at start or begin at start+n.
+// Return true if successful. Returns false if n is 0, the blocks run past the last block, any of them is already
+// free, or they would make all 2^64 blocks free: the free space is at most math.MaxUint64 blocks, so that every
+// extent length and the FreeBlocks count fit in a uint64.
+func (a *ExtentAllocator) Release(start, n uint64) bool {
+	if n == 0 || n-1 > math.MaxUint64-start {
+		return false
+	}
+	end := start + n - 1
+
+	lo, hi := start, end
+	prev, prevSize, prevOK := a.byStart.Last(start)
+	if prevOK {
+		if prevEnd := prev + prevSize - 1; prevEnd >= start {
+			return false
+		} else if prevEnd == start-1 {
+			lo = prev
+		} else {
+			prevOK = false
+		}
+	}
+	next, nextSize, nextOK := a.byStart.Next(start)
+	if nextOK {
+		if next <= end {
+			return false
+		} else if next == end+1 {
+			hi = next + nextSize - 1
+		} else {
+			nextOK = false
+		}
+	}
+	if lo == 0 && hi == math.MaxUint64 {
+		return false
+	}
+
+	if prevOK {
+		a.remove(prev, prevSize)
+	}
+	if nextOK {
+		a.remove(next, nextSize)
+	}
+	a.insert(lo, hi-lo+1)
+	a.free += n
+	return true
+}
+
+func (a *ExtentAllocator) insert(start, size uint64) {
+	a.byStart.Insert(start, size)
+	a.bySize.GetOrCreate(size).Set(start)
+}
+
+func (a *ExtentAllocator) remove(start, size uint64) {
+	a.byStart.Delete(start)
+	starts := a.bySize.Get(size)
+	starts.Unset(start)
+	if starts.array == nil {
+		a.bySize.Delete(size)
+	}
+}
+
+// IsFree tests if block is free.
+func (a *ExtentAllocator) IsFree(block uint64) bool {
+	start, size, ok := a.byStart.Last(block)
+	return ok && block-start < size
+}
+
+// Extents returns an iterator over the free extents, as their start and length, in ascending order of start.
+func (a *ExtentAllocator) Extents() iter.Seq2[uint64, uint64] {
+	return func(yield func(uint64, uint64) bool) {
+		for start, size, ok := a.byStart.First(0); ok; start, size, ok = a.byStart.Next(start) {
+			if !yield(start, size) {
+				return
+			}
+		}
+	}
+}
+
+// Stats returns statistics about the free space. Only the size index is searched, so it does not visit the
+// extents.
+func (a *ExtentAllocator) Stats() ExtentStats {
+	s := ExtentStats{FreeBlocks: a.free, Extents: a.byStart.CountAll()}
+	if size, _, ok := a.bySize.Last(math.MaxUint64); ok {
+		s.Largest = size
+	}
+	if size, _, ok := a.bySize.First(0); ok {
+		s.Smallest = size
+	}
+	if a.free > 0 {
+		s.Fragmentation = 1 - float64(s.Largest)/float64(a.free)
+	}
+	return s
+}
+
+// Return the number of bytes of memory currently in use by the allocator.
+func (a *ExtentAllocator) MemoryUsed() uint64 {
+	return a.byStart.MemoryUsed() + a.bySize.MemoryUsed()
+}
+
+// Free the allocator's arrays, forgetting every free extent.
+// Return the number of bytes freed.
+func (a *ExtentAllocator) Free() uint64 {
+	a.free = 0
+	return a.byStart.Free() + a.bySize.Free()
+}
@@ -0,0 +1,208 @@
+package judy
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestEmptyExtentAllocator(t *testing.T) {
+
+	a := ExtentAllocator{}
+	if r := a.Free(); r != 0 {
+		t.Errorf("Free should return 0, returned %v", r)
+	}
+	if _, ok := a.AllocateFirstFit(1); ok {
+		t.Error("Allocating from an empty allocator should fail")
+	}
+	if s := a.Stats(); s != (ExtentStats{}) {
+		t.Errorf("Stats of an empty allocator should be zero, were %+v", s)
+	}
+}
+
+func TestExtentAllocatorFits(t *testing.T) {
+
+	a := ExtentAllocator{}
+	defer a.Free()
+
+	// free extents 0-9, 20-23, 30-35
+	a.Release(0, 10)
+	a.Release(20, 4)
+	a.Release(30, 6)
+
+	if start, ok := a.AllocateBestFit(4); !ok || start != 20 {
+		t.Errorf("AllocateBestFit(4) should return 20, returned %v,%v", start, ok)
+	}
+	if start, ok := a.AllocateFirstFit(5); !ok || start != 0 {
+		t.Errorf("AllocateFirstFit(5) should return 0, returned %v,%v", start, ok)
+	}
+	// 5-9 and 30-35 are left
+	if start, ok := a.AllocateBestFit(5); !ok || start != 5 {
+		t.Errorf("AllocateBestFit(5) should return 5, returned %v,%v", start, ok)
+	}
+	if _, ok := a.AllocateFirstFit(7); ok {
+		t.Error("AllocateFirstFit(7) should fail")
+	}
+	if start, ok := a.AllocateFirstFit(2); !ok || start != 30 {
+		t.Errorf("AllocateFirstFit(2) should return 30, returned %v,%v", start, ok)
+	}
+	if _, ok := a.AllocateBestFit(0); ok {
+		t.Error("AllocateBestFit(0) should fail")
+	}
+
+	if !a.IsFree(33) || a.IsFree(31) || a.IsFree(36) {
+		t.Error("IsFree should reflect the free extents")
+	}
+	if s := a.Stats(); s.FreeBlocks != 4 || s.Extents != 1 || s.Fragmentation != 0 {
+		t.Errorf("Stats should be one extent of 4 blocks, were %+v", s)
+	}
+}
+
+func TestExtentAllocatorCoalesce(t *testing.T) {
+
+	a := ExtentAllocator{}
+	defer a.Free()
+
+	a.Release(10, 5)
+	a.Release(20, 5)
+	if s := a.Stats(); s.Extents != 2 || s.FreeBlocks != 10 || s.Fragmentation != 0.5 {
+		t.Errorf("Stats should be 2 extents of 10 blocks with fragmentation 0.5, were %+v", s)
+	}
+
+	// fills the hole between them
+	if !a.Release(15, 5) {
+		t.Error("Release(15, 5) should succeed")
+	}
+	var extents [][2]uint64
+	for start, size := range a.Extents() {
+		extents = append(extents, [2]uint64{start, size})
+	}
+	if len(extents) != 1 || extents[0] != [2]uint64{10, 15} {
+		t.Errorf("Extents should be coalesced to [10 15], were %v", extents)
+	}
+
+	if a.Release(12, 1) || a.Release(5, 6) || a.Release(24, 2) || a.Release(0, 0) {
+		t.Error("Releasing blocks that are already free should fail")
+	}
+	if a.Release(math.MaxUint64, 2) {
+		t.Error("Releasing past the last block should fail")
+	}
+	if !a.Release(math.MaxUint64, 1) || !a.IsFree(math.MaxUint64) {
+		t.Error("Releasing the last block should succeed")
+	}
+
+	if s := a.Stats(); s.Largest != 15 || s.Smallest != 1 || s.FreeBlocks != 16 {
+		t.Errorf("Stats should have largest 15 and smallest 1, were %+v", s)
+	}
+	if a.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
+
+func TestExtentAllocatorWholeSpace(t *testing.T) {
+
+	a := ExtentAllocator{}
+	defer a.Free()
+
+	// a release that overlaps the next extent leaves the previous one alone
+	a.Release(0, 10)
+	a.Release(20, 10)
+	if a.Release(10, 15) {
+		t.Error("Release(10, 15) should fail, as 20 is already free")
+	}
+	if s := a.Stats(); s.Extents != 2 || s.FreeBlocks != 20 {
+		t.Errorf("A failed release should not change the extents, stats were %+v", s)
+	}
+	a.Free()
+
+	if !a.Release(0, math.MaxUint64) {
+		t.Error("Release(0, MaxUint64) should succeed")
+	}
+	// all 2^64 blocks cannot be free
+	if a.Release(math.MaxUint64, 1) {
+		t.Error("Releasing the last block as well should fail")
+	}
+	if !a.IsFree(5) || a.IsFree(math.MaxUint64) {
+		t.Error("Every block but the last should be free")
+	}
+	if s := a.Stats(); s.FreeBlocks != math.MaxUint64 || s.Largest != math.MaxUint64 || s.Extents != 1 {
+		t.Errorf("Stats should be one extent of MaxUint64 blocks, were %+v", s)
+	}
+
+	// the same from the other end
+	a.Free()
+	a.Release(1, math.MaxUint64)
+	if a.Release(0, 1) || a.IsFree(0) || !a.IsFree(math.MaxUint64) {
+		t.Error("Releasing block 0 as well should fail")
+	}
+}
+
+func TestExtentAllocatorRandom(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(1))
+	a := ExtentAllocator{}
+	defer a.Free()
+
+	const blocks = 2000
+	a.Release(0, blocks)
+	used := make([]bool, blocks)
+	type extent struct{ start, size uint64 }
+	var allocated []extent
+
+	for i := 0; i < 2000; i++ {
+		if len(allocated) > 0 && rng.Intn(2) == 0 {
+			k := rng.Intn(len(allocated))
+			e := allocated[k]
+			allocated = append(allocated[:k], allocated[k+1:]...)
+			if !a.Release(e.start, e.size) {
+				t.Fatalf("Release(%v, %v) should succeed", e.start, e.size)
+			}
+			for b := e.start; b < e.start+e.size; b++ {
+				used[b] = false
+			}
+			continue
+		}
+
+		n := uint64(rng.Intn(20) + 1)
+		allocate := a.AllocateFirstFit
+		if rng.Intn(2) == 0 {
+			allocate = a.AllocateBestFit
+		}
+		start, ok := allocate(n)
+		if !ok {
+			continue
+		}
+		for b := start; b < start+n; b++ {
+			if used[b] {
+				t.Fatalf("Block %v should not be allocated twice", b)
+			}
+			used[b] = true
+		}
+		allocated = append(allocated, extent{start, n})
+	}
+
+	// the free extents are exactly the maximal runs of free blocks
+	var free uint64
+	prevEnd := int64(-2)
+	for start, size := range a.Extents() {
+		if int64(start) <= prevEnd+1 {
+			t.Fatalf("Extent at %v should have been coalesced with the one ending at %v", start, prevEnd)
+		}
+		for b := start; b < start+size; b++ {
+			if used[b] {
+				t.Fatalf("Block %v should not be both free and allocated", b)
+			}
+		}
+		free += size
+		prevEnd = int64(start + size - 1)
+	}
+	var expected uint64
+	for _, u := range used {
+		if !u {
+			expected++
+		}
+	}
+	if s := a.Stats(); free != expected || s.FreeBlocks != expected {
+		t.Errorf("Free blocks should be %v, were %v and %v", expected, free, s.FreeBlocks)
+	}
+}
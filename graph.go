@@ -0,0 +1,229 @@
+package judy
+
+import (
+	"iter"
+	"math"
+)
+
+// A Graph is a directed graph of uint64 node IDs, such as a follower graph, stored as two adjacency indexes: a
+// JudyL from each node to the Judy1 set of its successors (out-edges) and one from each node to the Judy1 set of
+// its predecessors (in-edges). An edge costs a few bytes in each index, and a node takes no memory until it has an
+// edge. The default value of this struct is a valid empty graph.
+//
+//    g := Graph{}
+//    defer g.Free()
+//
+//    g.AddEdge(follower, followee)
+//    for node, depth := range g.BFS(start) {
+//        ...
+//    }
+//
+// NOTE: The Judy arrays allocate memory outside of the Go runtime. It is very important that you call Free() on a
+// Graph after using it to prevent memory leaks.
+type Graph struct {
+	out JudyLOfJudy1
+	in  JudyLOfJudy1
+}
+
+// AddEdge adds the edge from -> to.
+// Return true if the edge was previously absent, otherwise false.
+func (g *Graph) AddEdge(from, to uint64) bool {
+	if !g.out.GetOrCreate(from).Set(to) {
+		return false
+	}
+	g.in.GetOrCreate(to).Set(from)
+	return true
+}
+
+// RemoveEdge removes the edge from -> to.
+// Return true if the edge was previously present, otherwise false.
+func (g *Graph) RemoveEdge(from, to uint64) bool {
+	if !unsetChild(&g.out, from, to) {
+		return false
+	}
+	unsetChild(&g.in, to, from)
+	return true
+}
+
+// RemoveNode removes every edge into and out of node.
+// Return the number of edges removed.
+func (g *Graph) RemoveNode(node uint64) uint64 {
+	var n uint64
+	if succ := g.out.Get(node); succ != nil {
+		for to, ok := succ.First(0); ok; to, ok = succ.Next(to) {
+			if to != node {
+				unsetChild(&g.in, to, node)
+			}
+			n++
+		}
+		g.out.Delete(node)
+	}
+	if pred := g.in.Get(node); pred != nil {
+		for from, ok := pred.First(0); ok; from, ok = pred.Next(from) {
+			if from != node {
+				unsetChild(&g.out, from, node)
+				n++
+			}
+		}
+		g.in.Delete(node)
+	}
+	return n
+}
+
+// unsetChild unsets bit in the child array of index, deleting the child if it becomes empty.
+func unsetChild(j *JudyLOfJudy1, index, bit uint64) bool {
+	child := j.Get(index)
+	if child == nil || !child.Unset(bit) {
+		return false
+	}
+	if child.array == nil {
+		j.Delete(index)
+	}
+	return true
+}
+
+// HasEdge tests if the edge from -> to is present.
+func (g *Graph) HasEdge(from, to uint64) bool {
+	succ := g.out.Get(from)
+	return succ != nil && succ.Test(to)
+}
+
+// OutDegree returns the number of edges out of node.
+func (g *Graph) OutDegree(node uint64) uint64 {
+	if succ := g.out.Get(node); succ != nil {
+		return succ.CountAll()
+	}
+	return 0
+}
+
+// InDegree returns the number of edges into node.
+func (g *Graph) InDegree(node uint64) uint64 {
+	if pred := g.in.Get(node); pred != nil {
+		return pred.CountAll()
+	}
+	return 0
+}
+
+// Count the number of edges in the graph.
+func (g *Graph) CountEdges() uint64 {
+	var n uint64
+	for _, succ := range g.out.All() {
+		n += succ.CountAll()
+	}
+	return n
+}
+
+// Successors returns an iterator over the nodes that node has an edge to, in ascending order.
+func (g *Graph) Successors(node uint64) iter.Seq[uint64] {
+	return neighbors(&g.out, node)
+}
+
+// Predecessors returns an iterator over the nodes that have an edge to node, in ascending order.
+func (g *Graph) Predecessors(node uint64) iter.Seq[uint64] {
+	return neighbors(&g.in, node)
+}
+
+// neighbors returns an iterator over the child array of node.
+func neighbors(j *JudyLOfJudy1, node uint64) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		set := j.Get(node)
+		if set == nil {
+			return
+		}
+		for n, ok := set.First(0); ok; n, ok = set.Next(n) {
+			if !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+// CommonNeighbors returns the number of nodes that both a and b have an edge to, counted by intersecting their
+// successor sets (see And).
+func (g *Graph) CommonNeighbors(a, b uint64) uint64 {
+	sa, sb := g.out.Get(a), g.out.Get(b)
+	if sa == nil || sb == nil {
+		return 0
+	}
+	return Count(And(sa, sb))
+}
+
+// BFS returns an iterator over the nodes reachable from start along out-edges in breadth-first order, with their
+// distance from start; start itself is yielded first at depth 0. The nodes of each depth are yielded in ascending
+// order. The visited set and frontier are Judy1 arrays, freed when the iteration ends.
+// The graph must not be modified during the traversal.
+func (g *Graph) BFS(start uint64) iter.Seq2[uint64, uint64] {
+	return func(yield func(uint64, uint64) bool) {
+		visited, frontier, next := Judy1{}, Judy1{}, Judy1{}
+		defer visited.Free()
+		defer func() {
+			frontier.Free()
+			next.Free()
+		}()
+
+		visited.Set(start)
+		frontier.Set(start)
+		for depth := uint64(0); frontier.array != nil; depth++ {
+			for node, ok := frontier.First(0); ok; node, ok = frontier.Next(node) {
+				if !yield(node, depth) {
+					return
+				}
+				succ := g.out.Get(node)
+				if succ == nil {
+					continue
+				}
+				for n, ok := succ.First(0); ok; n, ok = succ.Next(n) {
+					if visited.Set(n) {
+						next.Set(n)
+					}
+				}
+			}
+			frontier.Free()
+			frontier, next = next, Judy1{}
+		}
+	}
+}
+
+// DFS returns an iterator over the nodes reachable from start along out-edges in depth-first preorder, visiting
+// successors in ascending order; start itself is yielded first. The visited set is a Judy1 array, freed when the
+// iteration ends.
+// The graph must not be modified during the traversal.
+func (g *Graph) DFS(start uint64) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		visited := Judy1{}
+		defer visited.Free()
+
+		stack := []uint64{start}
+		for len(stack) > 0 {
+			node := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !visited.Set(node) {
+				continue
+			}
+			if !yield(node) {
+				return
+			}
+			succ := g.out.Get(node)
+			if succ == nil {
+				continue
+			}
+			// push in descending order so that the smallest successor is visited first
+			for n, ok := succ.Last(math.MaxUint64); ok; n, ok = succ.Prev(n) {
+				if !visited.Test(n) {
+					stack = append(stack, n)
+				}
+			}
+		}
+	}
+}
+
+// Return the number of bytes of memory currently in use by the graph.
+func (g *Graph) MemoryUsed() uint64 {
+	return g.out.MemoryUsed() + g.in.MemoryUsed()
+}
+
+// Free the entire graph.
+// Return the number of bytes freed.
+func (g *Graph) Free() uint64 {
+	return g.out.Free() + g.in.Free()
+}
@@ -0,0 +1,161 @@
+package judy
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestEmptyGraph(t *testing.T) {
+
+	g := Graph{}
+	if r := g.Free(); r != 0 {
+		t.Errorf("Free should return 0, returned %v", r)
+	}
+	if g.HasEdge(1, 2) || g.RemoveEdge(1, 2) {
+		t.Error("An empty graph should have no edges")
+	}
+	if got := slices.Collect(g.DFS(7)); !slices.Equal(got, []uint64{7}) {
+		t.Errorf("DFS on an empty graph should only visit the start, was %v", got)
+	}
+}
+
+func TestGraphEdges(t *testing.T) {
+
+	g := Graph{}
+	defer g.Free()
+
+	if !g.AddEdge(1, 2) || g.AddEdge(1, 2) {
+		t.Error("AddEdge(1, 2) should succeed once")
+	}
+	g.AddEdge(1, 3)
+	g.AddEdge(3, 2)
+	g.AddEdge(2, 2)
+	g.AddEdge(4, 1)
+
+	if !g.HasEdge(3, 2) || g.HasEdge(2, 3) {
+		t.Error("Edges should be directed")
+	}
+	if d := g.OutDegree(1); d != 2 {
+		t.Errorf("OutDegree(1) should be 2, was %v", d)
+	}
+	if d := g.InDegree(2); d != 3 {
+		t.Errorf("InDegree(2) should be 3, was %v", d)
+	}
+	if got := slices.Collect(g.Predecessors(2)); !slices.Equal(got, []uint64{1, 2, 3}) {
+		t.Errorf("Predecessors(2) should be [1 2 3], was %v", got)
+	}
+	if ct := g.CountEdges(); ct != 5 {
+		t.Errorf("CountEdges should be 5, was %v", ct)
+	}
+
+	if !g.RemoveEdge(1, 2) || g.RemoveEdge(1, 2) {
+		t.Error("RemoveEdge(1, 2) should succeed once")
+	}
+	if g.HasEdge(1, 2) || slices.Contains(slices.Collect(g.Predecessors(2)), 1) {
+		t.Error("RemoveEdge should remove both directions")
+	}
+
+	if n := g.RemoveNode(2); n != 2 {
+		t.Errorf("RemoveNode(2) should remove 2 edges, removed %v", n)
+	}
+	if g.OutDegree(3) != 0 || g.InDegree(2) != 0 || g.OutDegree(2) != 0 {
+		t.Error("RemoveNode should remove every edge of the node")
+	}
+	if ct := g.CountEdges(); ct != 2 {
+		t.Errorf("CountEdges should be 2, was %v", ct)
+	}
+	if g.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
+
+func TestGraphTraversal(t *testing.T) {
+
+	g := Graph{}
+	defer g.Free()
+
+	//  1 -> 2 -> 4 -> 6
+	//  |         ^
+	//  v         |
+	//  3 ------> 5 -> 1
+	g.AddEdge(1, 3)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 4)
+	g.AddEdge(3, 5)
+	g.AddEdge(5, 4)
+	g.AddEdge(5, 1)
+	g.AddEdge(4, 6)
+	g.AddEdge(7, 1)
+
+	var nodes, depths []uint64
+	for node, depth := range g.BFS(1) {
+		nodes = append(nodes, node)
+		depths = append(depths, depth)
+	}
+	if !slices.Equal(nodes, []uint64{1, 2, 3, 4, 5, 6}) || !slices.Equal(depths, []uint64{0, 1, 1, 2, 2, 3}) {
+		t.Errorf("BFS(1) should be [1 2 3 4 5 6] at depths [0 1 1 2 2 3], was %v at %v", nodes, depths)
+	}
+
+	if got := slices.Collect(g.DFS(1)); !slices.Equal(got, []uint64{1, 2, 4, 6, 3, 5}) {
+		t.Errorf("DFS(1) should be [1 2 4 6 3 5], was %v", got)
+	}
+
+	for node := range g.BFS(1) {
+		if node == 3 {
+			break
+		}
+	}
+
+	// a panic in the loop body, after the next level has been started, frees the traversal's arrays once
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("A panic in the BFS loop body should propagate")
+			}
+		}()
+		for node := range g.BFS(1) {
+			if node == 3 {
+				panic("stop")
+			}
+		}
+	}()
+}
+
+func TestGraphCommonNeighbors(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(1))
+	g := Graph{}
+	defer g.Free()
+
+	succ := map[uint64]map[uint64]bool{}
+	for i := 0; i < 2000; i++ {
+		from, to := uint64(rng.Intn(20)), uint64(rng.Intn(500))
+		g.AddEdge(from, to)
+		if succ[from] == nil {
+			succ[from] = map[uint64]bool{}
+		}
+		succ[from][to] = true
+	}
+
+	for a := uint64(0); a < 20; a++ {
+		if d := g.OutDegree(a); d != uint64(len(succ[a])) {
+			t.Errorf("OutDegree(%v) should be %v, was %v", a, len(succ[a]), d)
+		}
+		for b := uint64(0); b < 20; b++ {
+			var expected uint64
+			for to := range maps.Keys(succ[a]) {
+				if succ[b][to] {
+					expected++
+				}
+			}
+			if ct := g.CommonNeighbors(a, b); ct != expected {
+				t.Errorf("CommonNeighbors(%v, %v) should be %v, was %v", a, b, expected, ct)
+			}
+		}
+	}
+	if ct := g.CommonNeighbors(0, 1000); ct != 0 {
+		t.Errorf("CommonNeighbors with a node without edges should be 0, was %v", ct)
+	}
+}
@@ -0,0 +1,118 @@
+package judy
+
+import "math"
+
+// An IDAllocator hands out uint64 IDs, such as connection or session IDs, and reuses released ones: Allocate
+// always returns the lowest free ID. The IDs in use are the indexes of a Judy1 array, and free IDs are found with
+// FirstEmpty and the gaps between IDs in use, so no IDs are scanned one by one.
+// The default value of this struct is a valid allocator of every uint64 ID; use NewIDAllocator to limit the range.
+//
+//    a := judy.NewIDAllocator(1, 65535)
+//    defer a.Free()
+//
+//    id, ok := a.Allocate() // id == 1
+//    ...
+//    a.Release(id)
+//
+// NOTE: The underlying Judy1 array allocates memory outside of the Go runtime. It is very important that you call
+// Free() on an IDAllocator after using it to prevent memory leaks.
+type IDAllocator struct {
+	used Judy1
+	min  uint64
+	// max is stored inverted so that the default value of 0 means math.MaxUint64
+	notMax uint64
+}
+
+// NewIDAllocator returns an allocator of the IDs from min to max (inclusive).
+func NewIDAllocator(min, max uint64) *IDAllocator {
+	return &IDAllocator{min: min, notMax: ^max}
+}
+
+// Allocate the lowest free ID.
+//   returns (id, true) if an ID was allocated
+//   returns (_, false) if every ID is in use
+func (a *IDAllocator) Allocate() (uint64, bool) {
+	id, ok := a.used.FirstEmpty(a.min)
+	if !ok || id > ^a.notMax {
+		return 0, false
+	}
+	a.used.Set(id)
+	return id, true
+}
+
+// AllocateAt allocates id.
+// Return true if id was free and in range, otherwise false.
+func (a *IDAllocator) AllocateAt(id uint64) bool {
+	if id < a.min || id > ^a.notMax {
+		return false
+	}
+	return a.used.Set(id)
+}
+
+// AllocateRange allocates the lowest block of n contiguous free IDs, walking the gaps between the IDs in use.
+//   returns (first, true) if the IDs first to first+n-1 were allocated
+//   returns (_, false) if there is no such block, or n is 0
+func (a *IDAllocator) AllocateRange(n uint64) (uint64, bool) {
+	if n == 0 {
+		return 0, false
+	}
+	max := ^a.notMax
+	for from := a.min; ; {
+		first, ok := a.used.FirstEmpty(from)
+		if !ok || first > max {
+			return 0, false
+		}
+		last := max
+		if used, ok := a.used.Next(first); ok && used-1 < max {
+			last = used - 1
+		}
+		if last-first >= n-1 {
+			a.used.setRange(first, first+n-1)
+			return first, true
+		}
+		if last == max {
+			return 0, false
+		}
+		from = last + 1
+	}
+}
+
+// Release id so that it can be allocated again.
+// Return true if id was in use, otherwise false.
+func (a *IDAllocator) Release(id uint64) bool {
+	return a.used.Unset(id)
+}
+
+// ReleaseRange releases the n IDs from first to first+n-1, such as a block allocated with AllocateRange. A range
+// that would run past math.MaxUint64 ends there. Only the IDs in use are visited.
+func (a *IDAllocator) ReleaseRange(first, n uint64) {
+	if n == 0 {
+		return
+	}
+	last := uint64(math.MaxUint64)
+	if n-1 <= last-first {
+		last = first + n - 1
+	}
+	a.used.unsetRange(first, last)
+}
+
+// IsAllocated tests if id is in use.
+func (a *IDAllocator) IsAllocated(id uint64) bool {
+	return a.used.Test(id)
+}
+
+// InUse returns the number of IDs in use.
+func (a *IDAllocator) InUse() uint64 {
+	return a.used.CountAll()
+}
+
+// Return the number of bytes of memory currently in use by the allocator.
+func (a *IDAllocator) MemoryUsed() uint64 {
+	return a.used.MemoryUsed()
+}
+
+// Free the allocator, releasing every ID.
+// Return the number of bytes freed.
+func (a *IDAllocator) Free() uint64 {
+	return a.used.Free()
+}
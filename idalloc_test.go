@@ -0,0 +1,138 @@
+package judy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIDAllocatorDefault(t *testing.T) {
+
+	a := IDAllocator{}
+	defer a.Free()
+
+	for i := uint64(0); i < 10; i++ {
+		if id, ok := a.Allocate(); !ok || id != i {
+			t.Errorf("Allocate should return %v, returned %v,%v", i, id, ok)
+		}
+	}
+	if !a.Release(3) || a.Release(3) {
+		t.Error("Release(3) should succeed once")
+	}
+	if !a.Release(7) {
+		t.Error("Release(7) should succeed")
+	}
+	if id, _ := a.Allocate(); id != 3 {
+		t.Errorf("Allocate should reuse the lowest released ID 3, returned %v", id)
+	}
+	if id, _ := a.Allocate(); id != 7 {
+		t.Errorf("Allocate should reuse the released ID 7, returned %v", id)
+	}
+	if id, _ := a.Allocate(); id != 10 {
+		t.Errorf("Allocate should return 10, returned %v", id)
+	}
+
+	if !a.AllocateAt(math.MaxUint64) || a.AllocateAt(math.MaxUint64) {
+		t.Error("AllocateAt(MaxUint64) should succeed once")
+	}
+	if n := a.InUse(); n != 12 {
+		t.Errorf("InUse should be 12, was %v", n)
+	}
+	if !a.IsAllocated(5) || a.IsAllocated(11) {
+		t.Error("IsAllocated should reflect the IDs in use")
+	}
+	if a.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
+
+func TestIDAllocatorBounds(t *testing.T) {
+
+	a := NewIDAllocator(1, 4)
+	defer a.Free()
+
+	for i := uint64(1); i <= 4; i++ {
+		if id, ok := a.Allocate(); !ok || id != i {
+			t.Errorf("Allocate should return %v, returned %v,%v", i, id, ok)
+		}
+	}
+	if _, ok := a.Allocate(); ok {
+		t.Error("Allocate should fail when every ID is in use")
+	}
+	if a.AllocateAt(0) || a.AllocateAt(5) {
+		t.Error("AllocateAt should fail out of range")
+	}
+
+	a.Release(2)
+	if id, ok := a.Allocate(); !ok || id != 2 {
+		t.Errorf("Allocate should return 2, returned %v,%v", id, ok)
+	}
+}
+
+func TestIDAllocatorRange(t *testing.T) {
+
+	a := NewIDAllocator(100, 199)
+	defer a.Free()
+
+	a.AllocateAt(102)
+	a.AllocateAt(110)
+
+	// 100-101 and 103-109 are too small
+	if first, ok := a.AllocateRange(8); !ok || first != 111 {
+		t.Errorf("AllocateRange(8) should return 111, returned %v,%v", first, ok)
+	}
+	if first, ok := a.AllocateRange(7); !ok || first != 103 {
+		t.Errorf("AllocateRange(7) should return 103, returned %v,%v", first, ok)
+	}
+	if first, ok := a.AllocateRange(2); !ok || first != 100 {
+		t.Errorf("AllocateRange(2) should return 100, returned %v,%v", first, ok)
+	}
+	if n := a.InUse(); n != 19 {
+		t.Errorf("InUse should be 19, was %v", n)
+	}
+
+	// 119-199 is 81 IDs
+	if _, ok := a.AllocateRange(82); ok {
+		t.Error("AllocateRange(82) should not fit before the end of the range")
+	}
+	if first, ok := a.AllocateRange(81); !ok || first != 119 {
+		t.Errorf("AllocateRange(81) should return 119, returned %v,%v", first, ok)
+	}
+	if _, ok := a.AllocateRange(1); ok {
+		t.Error("AllocateRange should fail when every ID is in use")
+	}
+	if _, ok := a.AllocateRange(0); ok {
+		t.Error("AllocateRange(0) should fail")
+	}
+
+	a.ReleaseRange(111, 8)
+	if n := a.InUse(); n != 92 {
+		t.Errorf("InUse should be 92 after ReleaseRange, was %v", n)
+	}
+	if first, ok := a.AllocateRange(8); !ok || first != 111 {
+		t.Errorf("AllocateRange(8) should reuse 111, returned %v,%v", first, ok)
+	}
+
+	b := NewIDAllocator(math.MaxUint64-5, math.MaxUint64)
+	defer b.Free()
+	b.AllocateAt(math.MaxUint64 - 3)
+	if first, ok := b.AllocateRange(3); !ok || first != math.MaxUint64-2 {
+		t.Errorf("AllocateRange(3) should return MaxUint64-2, returned %v,%v", first, ok)
+	}
+	if _, ok := b.AllocateRange(3); ok {
+		t.Error("AllocateRange(3) should not fit")
+	}
+
+	// a range running past MaxUint64 is cut there rather than wrapping around
+	a.ReleaseRange(150, math.MaxUint64)
+	if n := a.InUse(); n != 50 || a.IsAllocated(150) || !a.IsAllocated(149) {
+		t.Errorf("ReleaseRange(150, MaxUint64) should release 150 .. 199, InUse was %v", n)
+	}
+	a.ReleaseRange(10, math.MaxUint64)
+	if n := a.InUse(); n != 0 {
+		t.Errorf("ReleaseRange(10, MaxUint64) should release every ID, InUse was %v", n)
+	}
+	b.ReleaseRange(math.MaxUint64-2, 5)
+	if n := b.InUse(); n != 1 {
+		t.Errorf("ReleaseRange(MaxUint64-2, 5) should release the 3 IDs at the top, InUse was %v", n)
+	}
+}
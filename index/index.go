@@ -0,0 +1,192 @@
+// Package index implements an inverted index of term IDs to document IDs, with a boolean query evaluator.
+//
+// Each term has a posting list, a judy.Judy1 array of the documents that contain it. Queries are trees of Term, And,
+// Or and Not nodes that are evaluated lazily as judy set expressions, seeking through the posting lists with First,
+// so results stream out in ascending document order without building intermediate sets.
+//
+//    ix := index.Index{}
+//    defer ix.Free()
+//
+//    ix.Add(golang, doc1)
+//    ix.Add(judy, doc1)
+//    for doc := range ix.Query(index.And(index.Term(golang), index.Not(index.Term(java)))) {
+//        fmt.Println(doc)
+//    }
+//
+// NOTE: The posting lists are Judy arrays, which allocate memory outside of the Go runtime. It is very important
+// that you call Free() on an Index after using it to prevent memory leaks.
+package index
+
+import (
+	"iter"
+
+	"github.com/gnoso/go-judy"
+)
+
+// An Index maps term IDs to posting lists of document IDs.
+// The default value of this struct is a valid empty index.
+type Index struct {
+	postings judy.JudyLOfJudy1
+	// docs counts the postings of every document, so that Not can be evaluated against all indexed documents.
+	docs judy.JudyL
+}
+
+// Add records that doc contains term.
+// Return true if the posting was added, false if it was already present.
+func (ix *Index) Add(term, doc uint64) bool {
+	if !ix.postings.GetOrCreate(term).Set(doc) {
+		return false
+	}
+	n, _ := ix.docs.Get(doc)
+	ix.docs.Insert(doc, n+1)
+	return true
+}
+
+// Remove records that doc no longer contains term. A term whose posting list becomes empty is removed.
+// Return true if the posting was removed, false if it was not present.
+func (ix *Index) Remove(term, doc uint64) bool {
+	list := ix.postings.Get(term)
+	if list == nil || !list.Unset(doc) {
+		return false
+	}
+	if list.CountAll() == 0 {
+		ix.postings.Delete(term)
+	}
+	if n, _ := ix.docs.Get(doc); n > 1 {
+		ix.docs.Insert(doc, n-1)
+	} else {
+		ix.docs.Delete(doc)
+	}
+	return true
+}
+
+// Contains reports whether doc contains term.
+func (ix *Index) Contains(term, doc uint64) bool {
+	list := ix.postings.Get(term)
+	return list != nil && list.Test(doc)
+}
+
+// DocFrequency returns the number of documents that contain term.
+func (ix *Index) DocFrequency(term uint64) uint64 {
+	if list := ix.postings.Get(term); list != nil {
+		return list.CountAll()
+	}
+	return 0
+}
+
+// Terms returns the number of terms with a posting list.
+func (ix *Index) Terms() uint64 {
+	return ix.postings.CountAll()
+}
+
+// Docs returns the number of distinct documents in the index.
+func (ix *Index) Docs() uint64 {
+	return ix.docs.CountAll()
+}
+
+// Query returns an iterator over the documents matching q, in ascending order.
+// The index must not be modified while the iterator is in use.
+func (ix *Index) Query(q Query) iter.Seq[uint64] {
+	return judy.Indexes(q.expr(ix))
+}
+
+// Count returns the number of documents matching q.
+func (ix *Index) Count(q Query) uint64 {
+	return judy.Count(q.expr(ix))
+}
+
+// Return the number of bytes of memory currently in use by the index.
+func (ix *Index) MemoryUsed() uint64 {
+	return ix.postings.MemoryUsed() + ix.docs.MemoryUsed()
+}
+
+// Free every posting list of the index.
+// Return the number of bytes freed.
+func (ix *Index) Free() uint64 {
+	return ix.postings.Free() + ix.docs.Free()
+}
+
+// A Query is a boolean expression over terms, built with Term, And, Or and Not.
+// Queries are evaluated as judy set expressions (see judy.Expr) over the posting lists.
+type Query interface {
+	expr(ix *Index) judy.Expr
+}
+
+// Term matches the documents that contain term.
+func Term(term uint64) Query {
+	return termQuery(term)
+}
+
+// And matches the documents that match every one of qs. Not operands are evaluated as exclusions, so
+// And(a, Not(b)) is a minus b, and the other operands are intersected starting from the one with the fewest
+// documents. And() with no operands matches nothing.
+func And(qs ...Query) Query {
+	return andQuery(qs)
+}
+
+// Or matches the documents that match any of qs. Or() with no operands matches nothing.
+func Or(qs ...Query) Query {
+	return orQuery(qs)
+}
+
+// Not matches the indexed documents that do not match q. On its own it is evaluated against every document in the
+// index; inside And it only excludes documents from the other operands, which is much cheaper.
+func Not(q Query) Query {
+	return notQuery{q}
+}
+
+type (
+	termQuery uint64
+	andQuery  []Query
+	orQuery   []Query
+	notQuery  struct{ q Query }
+)
+
+func (t termQuery) expr(ix *Index) judy.Expr {
+	if list := ix.postings.Get(uint64(t)); list != nil {
+		return list
+	}
+	return judy.Or()
+}
+
+func (a andQuery) expr(ix *Index) judy.Expr {
+	if len(a) == 0 {
+		return judy.Or()
+	}
+	xs := make([]judy.Expr, 0, len(a)+1)
+	positive := false
+	for _, q := range a {
+		if n, ok := q.(notQuery); ok {
+			xs = append(xs, judy.Not(n.q.expr(ix)))
+		} else {
+			xs = append(xs, q.expr(ix))
+			positive = true
+		}
+	}
+	if !positive {
+		xs = append(xs, docsExpr{&ix.docs})
+	}
+	return judy.And(xs...)
+}
+
+func (o orQuery) expr(ix *Index) judy.Expr {
+	xs := make([]judy.Expr, len(o))
+	for n, q := range o {
+		xs[n] = q.expr(ix)
+	}
+	return judy.Or(xs...)
+}
+
+func (n notQuery) expr(ix *Index) judy.Expr {
+	return judy.And(docsExpr{&ix.docs}, judy.Not(n.q.expr(ix)))
+}
+
+// docsExpr is the set of every document in the index.
+type docsExpr struct {
+	docs *judy.JudyL
+}
+
+func (e docsExpr) First(doc uint64) (uint64, bool) {
+	d, _, ok := e.docs.First(doc)
+	return d, ok
+}
@@ -0,0 +1,153 @@
+package index
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/gnoso/go-judy"
+)
+
+const (
+	golang = iota + 1
+	judyTerm
+	java
+	rust
+	missing
+)
+
+func newTestIndex() *Index {
+	ix := &Index{}
+	// doc -> terms
+	docs := map[uint64][]uint64{
+		1: {golang, judyTerm},
+		2: {golang},
+		3: {java},
+		4: {golang, java, rust},
+		5: {judyTerm, rust},
+		9: {golang, judyTerm, rust},
+	}
+	for doc, terms := range docs {
+		for _, term := range terms {
+			ix.Add(term, doc)
+		}
+	}
+	return ix
+}
+
+func collect(ix *Index, q Query) []uint64 {
+	return slices.Collect(ix.Query(q))
+}
+
+func TestIndexAddRemove(t *testing.T) {
+
+	ix := newTestIndex()
+	defer ix.Free()
+
+	if ix.Add(golang, 1) {
+		t.Error("Adding a present posting should return false")
+	}
+	if !ix.Contains(rust, 5) || ix.Contains(rust, 1) {
+		t.Error("Contains should report the postings")
+	}
+	if n := ix.DocFrequency(golang); n != 4 {
+		t.Errorf("DocFrequency(golang) should be 4, was %v", n)
+	}
+	if n := ix.Terms(); n != 4 {
+		t.Errorf("Terms should be 4, was %v", n)
+	}
+	if n := ix.Docs(); n != 6 {
+		t.Errorf("Docs should be 6, was %v", n)
+	}
+
+	if !ix.Remove(java, 3) || ix.Remove(java, 3) || ix.Remove(missing, 3) {
+		t.Error("Remove(java, 3) should succeed once")
+	}
+	if n := ix.Docs(); n != 5 {
+		t.Errorf("Doc 3 has no terms left, Docs should be 5, was %v", n)
+	}
+	if !ix.Remove(java, 4) || ix.Terms() != 3 {
+		t.Error("A term with no postings left should be removed")
+	}
+	if ix.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
+
+func TestIndexQuery(t *testing.T) {
+
+	ix := newTestIndex()
+	defer ix.Free()
+
+	tests := []struct {
+		name     string
+		q        Query
+		expected []uint64
+	}{
+		{"term", Term(golang), []uint64{1, 2, 4, 9}},
+		{"missing term", Term(missing), nil},
+		{"and", And(Term(golang), Term(rust)), []uint64{4, 9}},
+		{"and of three", And(Term(rust), Term(golang), Term(judyTerm)), []uint64{9}},
+		{"and with missing", And(Term(golang), Term(missing)), nil},
+		{"or", Or(Term(java), Term(judyTerm)), []uint64{1, 3, 4, 5, 9}},
+		{"or with missing", Or(Term(missing), Term(java)), []uint64{3, 4}},
+		{"and not", And(Term(golang), Not(Term(rust))), []uint64{1, 2}},
+		{"and not missing", And(Term(java), Not(Term(missing))), []uint64{3, 4}},
+		{"not", Not(Term(golang)), []uint64{3, 5}},
+		{"not not", Not(Not(Term(java))), []uint64{3, 4}},
+		{"and of nots", And(Not(Term(golang)), Not(Term(java))), []uint64{5}},
+		{"nested", Or(And(Term(golang), Not(Term(judyTerm))), And(Term(rust), Term(judyTerm))), []uint64{2, 4, 5, 9}},
+		{"empty and", And(), nil},
+		{"empty or", Or(), nil},
+	}
+
+	for _, tt := range tests {
+		if got := collect(ix, tt.q); !slices.Equal(got, tt.expected) {
+			t.Errorf("%v: expected %v, was %v", tt.name, tt.expected, got)
+		}
+		if n := ix.Count(tt.q); n != uint64(len(tt.expected)) {
+			t.Errorf("%v: count should be %v, was %v", tt.name, len(tt.expected), n)
+		}
+	}
+
+	for doc := range ix.Query(Term(golang)) {
+		if doc != 1 {
+			t.Errorf("Iteration should stop after the first document, got %v", doc)
+		}
+		break
+	}
+}
+
+func TestIndexQueryRandom(t *testing.T) {
+
+	ix := &Index{}
+	defer ix.Free()
+
+	rng := rand.New(rand.NewSource(1))
+	sets := make([]judy.Judy1, 4)
+	for n := range sets {
+		defer sets[n].Free()
+	}
+	for doc := uint64(0); doc < 5000; doc++ {
+		for term := range sets {
+			// terms of very different frequency, so that And reorders its operands
+			if rng.Intn(2<<term) == 0 {
+				ix.Add(uint64(term), doc)
+				sets[term].Set(doc)
+			}
+		}
+	}
+
+	q := Or(And(Term(0), Term(1), Not(Term(3))), And(Term(2), Not(Term(0))))
+	var expected []uint64
+	for doc := uint64(0); doc < 5000; doc++ {
+		a := sets[0].Test(doc) && sets[1].Test(doc) && !sets[3].Test(doc)
+		b := sets[2].Test(doc) && !sets[0].Test(doc)
+		if a || b {
+			expected = append(expected, doc)
+		}
+	}
+	if got := collect(ix, q); !slices.Equal(got, expected) {
+		t.Errorf("Query returned %v documents, expected %v", len(got), len(expected))
+	}
+}
@@ -0,0 +1,360 @@
+package judy
+
+import (
+	"iter"
+	"math"
+	"math/bits"
+	"net/netip"
+)
+
+// An IPSet is a set of IPv4 and IPv6 addresses, such as a firewall blocklist, built from CIDR prefixes.
+// The default value of this struct is a valid empty set.
+//
+//    s := IPSet{}
+//    defer s.Free()
+//
+//    s.AddPrefix(netip.MustParsePrefix("10.0.0.0/8"))
+//    s.RemovePrefix(netip.MustParsePrefix("10.1.0.0/16"))
+//    s.Contains(netip.MustParseAddr("10.2.3.4")) // true
+//    for p := range s.Prefixes() {
+//        fmt.Println(p) // 10.0.0.0/16, 10.2.0.0/15, 10.4.0.0/14, ...
+//    }
+//
+// IPv4 addresses are the indexes of a Judy1 array, so a prefix costs one bit per address. Adding a prefix fills it in
+// a single call into C that jumps over the addresses already in the set, and removing one only visits the addresses
+// in the set. The IPv6 address space is too large for that, so IPv6 addresses are kept as maximal runs of consecutive
+// addresses, with the first and last address of every run in a pair of Set128s. Adding or removing a prefix of any
+// length, up to ::/0, only touches the runs it overlaps.
+//
+// Addresses are compared exactly as netip.Addr does: an IPv4-mapped IPv6 address is an IPv6 address, and zones are
+// ignored.
+//
+// NOTE: The Judy arrays allocate memory outside of the Go runtime. It is very important that you call Free() on an
+// IPSet after using it to prevent memory leaks.
+type IPSet struct {
+	v4 Judy1
+	v6 ranges128
+}
+
+// Add addr to the set.
+func (s *IPSet) Add(addr netip.Addr) {
+	s.AddPrefix(netip.PrefixFrom(addr, addr.BitLen()))
+}
+
+// Remove addr from the set.
+func (s *IPSet) Remove(addr netip.Addr) {
+	s.RemovePrefix(netip.PrefixFrom(addr, addr.BitLen()))
+}
+
+// AddPrefix adds every address in p to the set. Invalid prefixes are ignored.
+func (s *IPSet) AddPrefix(p netip.Prefix) {
+	s.update(p, true)
+}
+
+// RemovePrefix removes every address in p from the set. Invalid prefixes are ignored.
+func (s *IPSet) RemovePrefix(p netip.Prefix) {
+	s.update(p, false)
+}
+
+func (s *IPSet) update(p netip.Prefix, add bool) {
+	if !p.IsValid() {
+		return
+	}
+	lo, hi := prefixRange(p)
+	if p.Addr().Is4() {
+		updateRange(&s.v4, lo.Lo, hi.Lo, add)
+		return
+	}
+
+	if add {
+		s.v6.add(lo, hi)
+	} else {
+		s.v6.remove(lo, hi)
+	}
+}
+
+// updateRange sets or unsets every index from lo to hi (inclusive), with setRange or unsetRange.
+func updateRange(j *Judy1, lo, hi uint64, set bool) {
+	if set {
+		j.setRange(lo, hi)
+	} else {
+		j.unsetRange(lo, hi)
+	}
+}
+
+// Contains tests if addr is in the set.
+func (s *IPSet) Contains(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+	k := addrKey(addr)
+	if addr.Is4() {
+		return s.v4.Test(k.Lo)
+	}
+	return s.v6.contains(k, k)
+}
+
+// ContainsPrefix tests if every address in p is in the set.
+func (s *IPSet) ContainsPrefix(p netip.Prefix) bool {
+	if !p.IsValid() {
+		return false
+	}
+	first, last := prefixRange(p)
+	if p.Addr().Is4() {
+		return s.v4.CountFrom(first.Lo, last.Lo) == last.Lo-first.Lo+1
+	}
+	return s.v6.contains(first, last)
+}
+
+// Count4 returns the number of IPv4 addresses in the set.
+func (s *IPSet) Count4() uint64 {
+	return s.v4.CountAll()
+}
+
+// Count6 returns the number of IPv6 addresses in the set, as the 128-bit number hi<<64 | lo. The count of all 2^128
+// addresses wraps to 0.
+func (s *IPSet) Count6() (hi, lo uint64) {
+	return s.CountPrefix(netip.PrefixFrom(netip.IPv6Unspecified(), 0))
+}
+
+// CountPrefix returns the number of addresses of p in the set, as the 128-bit number hi<<64 | lo (hi is always 0
+// for IPv4). No addresses are scanned: IPv4 counts come from CountFrom, and IPv6 counts add up the lengths of the
+// runs that overlap p.
+func (s *IPSet) CountPrefix(p netip.Prefix) (hi, lo uint64) {
+	if !p.IsValid() {
+		return 0, 0
+	}
+	first, last := prefixRange(p)
+	if p.Addr().Is4() {
+		return 0, s.v4.CountFrom(first.Lo, last.Lo)
+	}
+
+	for start, end := range s.v6.runs(first, last) {
+		n := key128Inc(key128Sub(end, start))
+		var carry uint64
+		lo, carry = bits.Add64(lo, n.Lo, 0)
+		hi += n.Hi + carry
+	}
+	return hi, lo
+}
+
+// Prefixes returns an iterator over the minimal list of CIDR prefixes that covers exactly the addresses in the set,
+// IPv4 first and then IPv6, in ascending address order. IPv4 runs of consecutive addresses are found with First and
+// NextEmpty and IPv6 runs are stored as such, so the cost depends on the number of runs, not the number of addresses.
+// The set must not be modified during the iteration.
+func (s *IPSet) Prefixes() iter.Seq[netip.Prefix] {
+	return func(yield func(netip.Prefix) bool) {
+		for lo, ok := s.v4.First(0); ok; {
+			hi := uint64(math.MaxUint32)
+			if e, ok := s.v4.NextEmpty(lo); ok {
+				hi = e - 1
+			}
+			if !rangePrefixes(Key128{Lo: lo}, Key128{Lo: hi}, 32, yield) {
+				return
+			}
+			lo, ok = s.v4.Next(hi)
+		}
+		for lo, hi := range s.v6.runs(Key128{}, maxKey128) {
+			if !rangePrefixes(lo, hi, 128, yield) {
+				return
+			}
+		}
+	}
+}
+
+// A ranges128 is a set of 128-bit keys kept as maximal runs of consecutive keys, with the first key of every run in
+// starts and the last in ends. Runs never overlap or touch, so the end of the run starting at k is the first key in
+// ends at or after k.
+type ranges128 struct {
+	starts, ends Set128
+}
+
+// find returns the run that contains k, or else the last run before k.
+//   returns (lo, hi, true) if there is a run starting at or before k
+//   returns (_, _, false) if there is none
+func (r *ranges128) find(k Key128) (Key128, Key128, bool) {
+	lo, ok := r.starts.Last(k)
+	if !ok {
+		return Key128{}, Key128{}, false
+	}
+	hi, _ := r.ends.First(lo)
+	return lo, hi, true
+}
+
+// contains tests if every key from lo to hi (inclusive) is in the set.
+func (r *ranges128) contains(lo, hi Key128) bool {
+	_, end, ok := r.find(lo)
+	return ok && end.Compare(hi) >= 0
+}
+
+// add every key from lo to hi (inclusive), merging the runs it overlaps or touches.
+func (r *ranges128) add(lo, hi Key128) {
+	if start, end, ok := r.find(lo); ok && (end.Compare(lo) >= 0 || key128Inc(end) == lo) {
+		lo = start
+	}
+	if hi != maxKey128 {
+		if _, end, ok := r.find(key128Inc(hi)); ok && end.Compare(hi) > 0 {
+			hi = end
+		}
+	}
+	r.clear(lo, hi)
+	r.starts.Set(lo)
+	r.ends.Set(hi)
+}
+
+// remove every key from lo to hi (inclusive), cutting the runs that reach outside it.
+func (r *ranges128) remove(lo, hi Key128) {
+	start, end, ok := r.find(lo)
+	left := ok && start.Compare(lo) < 0 && end.Compare(lo) >= 0
+	_, end, ok = r.find(hi)
+	right := ok && end.Compare(hi) > 0
+	r.clear(lo, hi)
+	if left {
+		r.ends.Set(key128Dec(lo))
+	}
+	if right {
+		r.starts.Set(key128Inc(hi))
+	}
+}
+
+// clear removes the run boundaries from lo to hi (inclusive).
+func (r *ranges128) clear(lo, hi Key128) {
+	for _, keys := range []*Set128{&r.starts, &r.ends} {
+		for k, ok := keys.First(lo); ok && k.Compare(hi) <= 0; k, ok = keys.Next(k) {
+			keys.Unset(k)
+		}
+	}
+}
+
+// runs returns an iterator over the runs that overlap lo..hi, cut to lo..hi, in ascending order.
+func (r *ranges128) runs(lo, hi Key128) iter.Seq2[Key128, Key128] {
+	return func(yield func(Key128, Key128) bool) {
+		start, ok := r.starts.Last(lo)
+		if end, _ := r.ends.First(start); !ok || end.Compare(lo) < 0 {
+			start, ok = r.starts.First(lo)
+		}
+		for ; ok && start.Compare(hi) <= 0; start, ok = r.starts.Next(start) {
+			end, _ := r.ends.First(start)
+			if !yield(key128Max(start, lo), key128Min(end, hi)) {
+				return
+			}
+		}
+	}
+}
+
+func (r *ranges128) MemoryUsed() uint64 {
+	return r.starts.MemoryUsed() + r.ends.MemoryUsed()
+}
+
+func (r *ranges128) Free() uint64 {
+	return r.starts.Free() + r.ends.Free()
+}
+
+// rangePrefixes yields the minimal list of prefixes that covers lo..hi (inclusive), for addresses of bitLen bits.
+func rangePrefixes(lo, hi Key128, bitLen int, yield func(netip.Prefix) bool) bool {
+	for {
+		// the largest aligned block that starts at lo and ends at or before hi
+		size := bitLen
+		if tz := key128TrailingZeros(lo); tz < size {
+			size = tz
+		}
+		var last Key128
+		for {
+			last = key128Or(lo, size)
+			if last.Compare(hi) <= 0 {
+				break
+			}
+			size--
+		}
+		if !yield(netip.PrefixFrom(keyAddr(lo, bitLen), bitLen-size)) {
+			return false
+		}
+		if last == hi {
+			return true
+		}
+		lo = key128Inc(last)
+	}
+}
+
+// prefixRange returns the keys of the first and last addresses of p.
+func prefixRange(p netip.Prefix) (Key128, Key128) {
+	p = p.Masked()
+	lo := addrKey(p.Addr())
+	return lo, key128Or(lo, p.Addr().BitLen()-p.Bits())
+}
+
+// addrKey returns an IPv6 address as its big-endian Key128, and an IPv4 address in Lo.
+func addrKey(addr netip.Addr) Key128 {
+	if addr.Is4() {
+		b := addr.As4()
+		return Key128{Lo: uint64(b[0])<<24 | uint64(b[1])<<16 | uint64(b[2])<<8 | uint64(b[3])}
+	}
+	return Key128FromBytes(addr.As16())
+}
+
+// keyAddr is the inverse of addrKey.
+func keyAddr(k Key128, bitLen int) netip.Addr {
+	if bitLen == 32 {
+		return netip.AddrFrom4([4]byte{byte(k.Lo >> 24), byte(k.Lo >> 16), byte(k.Lo >> 8), byte(k.Lo)})
+	}
+	return netip.AddrFrom16(k.Bytes())
+}
+
+// key128Or returns k with its low n bits set.
+func key128Or(k Key128, n int) Key128 {
+	switch {
+	case n >= 128:
+		return maxKey128
+	case n >= 64:
+		return Key128{k.Hi | (1<<(n-64) - 1), math.MaxUint64}
+	}
+	return Key128{k.Hi, k.Lo | (1<<n - 1)}
+}
+
+func key128Inc(k Key128) Key128 {
+	lo, carry := bits.Add64(k.Lo, 1, 0)
+	return Key128{k.Hi + carry, lo}
+}
+
+func key128Dec(k Key128) Key128 {
+	lo, borrow := bits.Sub64(k.Lo, 1, 0)
+	return Key128{k.Hi - borrow, lo}
+}
+
+// key128Sub returns a - b, wrapping around.
+func key128Sub(a, b Key128) Key128 {
+	lo, borrow := bits.Sub64(a.Lo, b.Lo, 0)
+	return Key128{a.Hi - b.Hi - borrow, lo}
+}
+
+func key128Min(a, b Key128) Key128 {
+	if a.Compare(b) < 0 {
+		return a
+	}
+	return b
+}
+
+func key128Max(a, b Key128) Key128 {
+	if a.Compare(b) > 0 {
+		return a
+	}
+	return b
+}
+
+func key128TrailingZeros(k Key128) int {
+	if k.Lo != 0 {
+		return bits.TrailingZeros64(k.Lo)
+	}
+	return 64 + bits.TrailingZeros64(k.Hi)
+}
+
+// Return the number of bytes of memory currently in use by the set.
+func (s *IPSet) MemoryUsed() uint64 {
+	return s.v4.MemoryUsed() + s.v6.MemoryUsed()
+}
+
+// Free the entire set.
+// Return the number of bytes freed.
+func (s *IPSet) Free() uint64 {
+	return s.v4.Free() + s.v6.Free()
+}
@@ -0,0 +1,318 @@
+package judy
+
+import (
+	"math"
+	"math/rand"
+	"net/netip"
+	"slices"
+	"testing"
+)
+
+func TestEmptyIPSet(t *testing.T) {
+
+	s := IPSet{}
+	if r := s.Free(); r != 0 {
+		t.Errorf("Free should return 0, returned %v", r)
+	}
+	if s.Contains(netip.MustParseAddr("10.0.0.1")) || s.Contains(netip.MustParseAddr("::1")) || s.Contains(netip.Addr{}) {
+		t.Error("An empty set should contain nothing")
+	}
+	if s.ContainsPrefix(netip.MustParsePrefix("::/0")) {
+		t.Error("An empty set should not contain ::/0")
+	}
+	for p := range s.Prefixes() {
+		t.Errorf("An empty set should have no prefixes, had %v", p)
+	}
+}
+
+func TestIPSetIPv4(t *testing.T) {
+
+	s := IPSet{}
+	defer s.Free()
+
+	s.AddPrefix(netip.MustParsePrefix("10.0.0.0/16"))
+	s.RemovePrefix(netip.MustParsePrefix("10.0.1.0/24"))
+	s.Add(netip.MustParseAddr("192.168.1.1"))
+	s.AddPrefix(netip.MustParsePrefix("255.255.255.254/31"))
+	s.AddPrefix(netip.Prefix{})
+
+	if !s.Contains(netip.MustParseAddr("10.0.2.3")) || s.Contains(netip.MustParseAddr("10.0.1.3")) {
+		t.Error("Contains should reflect the removed /24")
+	}
+	if s.Contains(netip.MustParseAddr("::ffff:10.0.2.3")) {
+		t.Error("An IPv4-mapped IPv6 address should not be in an IPv4 prefix")
+	}
+	if ct := s.Count4(); ct != 65536-256+1+2 {
+		t.Errorf("Count4 should be %v, was %v", 65536-256+1+2, ct)
+	}
+	if _, ct := s.CountPrefix(netip.MustParsePrefix("10.0.0.0/23")); ct != 256 {
+		t.Errorf("CountPrefix(10.0.0.0/23) should be 256, was %v", ct)
+	}
+	if !s.ContainsPrefix(netip.MustParsePrefix("10.0.128.0/17")) || s.ContainsPrefix(netip.MustParsePrefix("10.0.0.0/16")) {
+		t.Error("ContainsPrefix should only be true for fully covered prefixes")
+	}
+
+	var got []string
+	for p := range s.Prefixes() {
+		got = append(got, p.String())
+	}
+	expected := []string{"10.0.0.0/24", "10.0.2.0/23", "10.0.4.0/22", "10.0.8.0/21", "10.0.16.0/20", "10.0.32.0/19",
+		"10.0.64.0/18", "10.0.128.0/17", "192.168.1.1/32", "255.255.255.254/31"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Prefixes should be %v, was %v", expected, got)
+	}
+
+	s.Remove(netip.MustParseAddr("192.168.1.1"))
+	if s.Contains(netip.MustParseAddr("192.168.1.1")) {
+		t.Error("Remove should remove the address")
+	}
+	if s.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
+
+func TestIPSetIPv4Large(t *testing.T) {
+
+	s := IPSet{}
+	defer s.Free()
+
+	s.Add(netip.MustParseAddr("192.0.2.1"))
+	s.AddPrefix(netip.MustParsePrefix("10.0.0.0/25"))
+	s.AddPrefix(netip.MustParsePrefix("10.0.0.0/24"))
+	if ct := s.Count4(); ct != 257 {
+		t.Errorf("Count4 should be 257, was %v", ct)
+	}
+
+	// removing every address only visits the ones in the set
+	s.RemovePrefix(netip.MustParsePrefix("0.0.0.0/0"))
+	if ct := s.Count4(); ct != 0 {
+		t.Errorf("Removing 0.0.0.0/0 should leave no addresses, Count4 was %v", ct)
+	}
+}
+
+func TestIPSetIPv6(t *testing.T) {
+
+	s := IPSet{}
+	defer s.Free()
+
+	s.AddPrefix(netip.MustParsePrefix("2001:db8::/62"))
+	s.RemovePrefix(netip.MustParsePrefix("2001:db8:0:1::/120"))
+	s.AddPrefix(netip.MustParsePrefix("2001:db8:0:10::/126"))
+	s.Add(netip.MustParseAddr("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"))
+
+	tests := []struct {
+		addr     string
+		expected bool
+	}{
+		{"2001:db8::1", true},
+		{"2001:db8:0:1::ff", false},
+		{"2001:db8:0:1::100", true},
+		{"2001:db8:0:3:ffff:ffff:ffff:ffff", true},
+		{"2001:db8:0:4::", false},
+		{"2001:db8:0:10::3", true},
+		{"2001:db8:0:10::4", false},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", true},
+		{"10.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := s.Contains(netip.MustParseAddr(tt.addr)); got != tt.expected {
+			t.Errorf("Contains(%v) should be %v", tt.addr, tt.expected)
+		}
+	}
+
+	// 4 networks less 256 addresses, plus 4 and 1 addresses
+	if hi, lo := s.Count6(); hi != 3 || lo != math.MaxUint64-250 {
+		t.Errorf("Count6 should be 3<<64 | %v, was %v<<64 | %v", uint64(math.MaxUint64-250), hi, lo)
+	}
+	if hi, lo := s.CountPrefix(netip.MustParsePrefix("2001:db8:0:1::/112")); hi != 0 || lo != 65536-256 {
+		t.Errorf("CountPrefix(2001:db8:0:1::/112) should be %v, was %v,%v", 65536-256, hi, lo)
+	}
+	if !s.ContainsPrefix(netip.MustParsePrefix("2001:db8:0:2::/63")) || s.ContainsPrefix(netip.MustParsePrefix("2001:db8::/62")) {
+		t.Error("ContainsPrefix should only be true for fully covered prefixes")
+	}
+
+	prefixes := slices.Collect(s.Prefixes())
+	if len(prefixes) != 60 {
+		t.Errorf("Prefixes should have 60 prefixes, had %v: %v", len(prefixes), prefixes)
+	}
+	if first := prefixes[0].String(); first != "2001:db8::/64" {
+		t.Errorf("The first prefix should be 2001:db8::/64, was %v", first)
+	}
+	if p := prefixes[57].String(); p != "2001:db8:0:2::/63" {
+		t.Errorf("The prefix after the holed network should be 2001:db8:0:2::/63, was %v", p)
+	}
+	checkMinimalCover(t, prefixes)
+
+	// removing the rest of the holed network merges nothing but leaves two runs and the last address
+	s.RemovePrefix(netip.MustParsePrefix("2001:db8:0:1::/64"))
+	s.RemovePrefix(netip.MustParsePrefix("2001:db8:0:10::/64"))
+	if ct := s.v6.starts.CountAll(); ct != 3 {
+		t.Errorf("There should be 3 runs of addresses, there were %v", ct)
+	}
+
+	// adding back a removed address to a fully covered network
+	s.RemovePrefix(netip.MustParsePrefix("2001:db8::5/128"))
+	s.Add(netip.MustParseAddr("2001:db8::5"))
+	if !s.ContainsPrefix(netip.MustParsePrefix("2001:db8::/64")) {
+		t.Error("Re-adding an address should restore the full network")
+	}
+}
+
+func TestIPSetIPv6Large(t *testing.T) {
+
+	s := IPSet{}
+	defer s.Free()
+
+	s.AddPrefix(netip.MustParsePrefix("::/0"))
+	if !s.ContainsPrefix(netip.MustParsePrefix("::/0")) || !s.Contains(netip.MustParseAddr("::")) {
+		t.Error("::/0 should contain every address")
+	}
+	if hi, lo := s.Count6(); hi != 0 || lo != 0 {
+		t.Errorf("The count of every IPv6 address should wrap to 0, was %v,%v", hi, lo)
+	}
+	if s.Contains(netip.MustParseAddr("10.0.0.1")) {
+		t.Error("::/0 should not contain IPv4 addresses")
+	}
+
+	s.RemovePrefix(netip.MustParsePrefix("2001:db8::/32"))
+	s.Remove(netip.MustParseAddr("::"))
+	if s.Contains(netip.MustParseAddr("2001:db8:1::1")) || !s.Contains(netip.MustParseAddr("2001:db9::")) {
+		t.Error("Only the removed /32 should be missing")
+	}
+	if hi, lo := s.Count6(); hi != math.MaxUint64-1<<32 || lo != math.MaxUint64 {
+		t.Errorf("Count6 should be 2^128 less 2^96 and 1, was %v,%v", hi, lo)
+	}
+	want := []string{"::1/128", "::2/127", "::4/126"}
+	got := slices.Collect(s.Prefixes())
+	if len(got) < 3 || got[0].String() != want[0] || got[1].String() != want[1] || got[2].String() != want[2] {
+		t.Errorf("Prefixes should start with %v, was %v", want, got)
+	}
+	checkMinimalCover(t, got)
+	cover := IPSet{}
+	defer cover.Free()
+	for _, p := range got {
+		cover.AddPrefix(p)
+	}
+	if hi, lo := cover.Count6(); hi != math.MaxUint64-1<<32 || lo != math.MaxUint64 {
+		t.Errorf("The prefixes should cover the same addresses, count was %v,%v", hi, lo)
+	}
+
+	s.AddPrefix(netip.MustParsePrefix("2001:db8::/32"))
+	s.Add(netip.MustParseAddr("::"))
+	if p := slices.Collect(s.Prefixes()); len(p) != 1 || p[0].String() != "::/0" {
+		t.Errorf("Adding the /32 and :: back should leave ::/0, was %v", p)
+	}
+	if ct := s.v6.starts.CountAll(); ct != 1 {
+		t.Errorf("There should be 1 run of addresses, there were %v", ct)
+	}
+
+	s.RemovePrefix(netip.MustParsePrefix("::/0"))
+	if hi, lo := s.Count6(); hi != 0 || lo != 0 || s.MemoryUsed() != 0 {
+		t.Errorf("Removing ::/0 should leave an empty set, count was %v,%v", hi, lo)
+	}
+
+	// a /32 on its own, with an address removed and added back at each end
+	p := netip.MustParsePrefix("2600::/32")
+	s.AddPrefix(p)
+	if hi, lo := s.CountPrefix(p); hi != 1<<32 || lo != 0 {
+		t.Errorf("CountPrefix of a full /32 should be 2^96, was %v,%v", hi, lo)
+	}
+	if hi, lo := s.CountPrefix(netip.MustParsePrefix("2600::/31")); hi != 1<<32 || lo != 0 {
+		t.Errorf("CountPrefix of the enclosing /31 should be 2^96, was %v,%v", hi, lo)
+	}
+	first, last := netip.MustParseAddr("2600::"), netip.MustParseAddr("2600:0:ffff:ffff:ffff:ffff:ffff:ffff")
+	s.Remove(first)
+	s.Remove(last)
+	if s.ContainsPrefix(p) || s.Contains(first) || s.Contains(last) || !s.Contains(first.Next()) {
+		t.Error("The ends of the /32 should be removed")
+	}
+	s.Add(first)
+	s.Add(last)
+	if !s.ContainsPrefix(p) || s.v6.starts.CountAll() != 1 {
+		t.Error("Adding the ends back should restore the /32 as one run")
+	}
+}
+
+// checkMinimalCover checks that prefixes are in ascending order, disjoint, and that no two consecutive prefixes
+// could be merged into their parent.
+func checkMinimalCover(t *testing.T, prefixes []netip.Prefix) {
+	for i := 1; i < len(prefixes); i++ {
+		p, q := prefixes[i-1], prefixes[i]
+		_, last := prefixRange(p)
+		if addrKey(q.Addr()).Compare(last) <= 0 {
+			t.Errorf("Prefixes %v and %v should be ascending and disjoint", p, q)
+		}
+		if p.Bits() == q.Bits() && p.Bits() > 0 {
+			parent := netip.PrefixFrom(p.Addr(), p.Bits()-1).Masked()
+			if parent.Addr() == p.Addr() && parent.Contains(q.Addr()) {
+				t.Errorf("Prefixes %v and %v should have been merged", p, q)
+			}
+		}
+	}
+}
+
+func TestIPSetPrefixesRandom(t *testing.T) {
+
+	for _, base := range []string{"172.16.0.0", "2001:db8::"} {
+		testIPSetPrefixesRandom(t, netip.MustParseAddr(base))
+	}
+}
+
+// testIPSetPrefixesRandom adds and removes random prefixes in the 4096 addresses from base, and checks the set
+// against a slice of bools.
+func testIPSetPrefixesRandom(t *testing.T, base netip.Addr) {
+
+	rng := rand.New(rand.NewSource(1))
+	s := IPSet{}
+	defer s.Free()
+
+	bitLen := base.BitLen()
+	present := make([]bool, 4096)
+	addr := func(i int) netip.Addr {
+		b := base.As16()
+		b[14], b[15] = byte(i>>8), byte(i)
+		if base.Is4() {
+			return netip.AddrFrom16(b).Unmap()
+		}
+		return netip.AddrFrom16(b)
+	}
+
+	for i := 0; i < 200; i++ {
+		bits := bitLen - 12 + rng.Intn(13)
+		start := rng.Intn(4096) &^ (1<<(bitLen-bits) - 1)
+		add := rng.Intn(3) > 0
+		if add {
+			s.AddPrefix(netip.PrefixFrom(addr(start), bits))
+		} else {
+			s.RemovePrefix(netip.PrefixFrom(addr(start), bits))
+		}
+		for j := start; j < start+1<<(bitLen-bits) && j < 4096; j++ {
+			present[j] = add
+		}
+	}
+
+	prefixes := slices.Collect(s.Prefixes())
+	checkMinimalCover(t, prefixes)
+
+	cover := IPSet{}
+	defer cover.Free()
+	for _, p := range prefixes {
+		cover.AddPrefix(p)
+	}
+	var expected uint64
+	for i, ok := range present {
+		if ok {
+			expected++
+		}
+		if s.Contains(addr(i)) != ok || cover.Contains(addr(i)) != ok {
+			t.Fatalf("Contains(%v) should be %v", addr(i), ok)
+		}
+	}
+	ct := cover.Count4()
+	if base.Is6() {
+		_, ct = cover.Count6()
+	}
+	if ct != expected {
+		t.Errorf("The prefixes from %v should cover %v addresses, covered %v", base, expected, ct)
+	}
+}
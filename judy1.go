@@ -13,11 +13,66 @@ package judy
 /*
 #cgo LDFLAGS: -lJudy
 #include <Judy.h>
+
+// Fill keys with up to limit indexes present in the array, starting with idx (which must be present).
+static size_t judy1Fill(Pcvoid_t array, Word_t idx, Word_t *keys, size_t limit) {
+	size_t n = 0;
+	for (;;) {
+		keys[n++] = idx;
+		if (n == limit || Judy1Next(array, &idx, NULL) == 0) {
+			return n;
+		}
+	}
+}
+
+// Fill keys with up to limit indexes present in the array, starting with the nth index present.
+static size_t judy1PageByCount(Pcvoid_t array, Word_t nth, Word_t *keys, size_t limit) {
+	Word_t idx;
+	if (Judy1ByCount(array, nth, &idx, NULL) == 0) {
+		return 0;
+	}
+	return judy1Fill(array, idx, keys, limit);
+}
+
+// Fill keys with up to limit indexes present in the array that are greater than index.
+static size_t judy1PageAfter(Pcvoid_t array, Word_t index, Word_t *keys, size_t limit) {
+	if (Judy1Next(array, &index, NULL) == 0) {
+		return 0;
+	}
+	return judy1Fill(array, index, keys, limit);
+}
+
+// Set every index from indexA to indexB (inclusive), jumping over the runs already set with Judy1FirstEmpty.
+// Return the number of indexes set.
+static Word_t judy1SetRange(PPvoid_t parray, Word_t indexA, Word_t indexB) {
+	Word_t n = 0, idx = indexA;
+	while (Judy1FirstEmpty(*parray, &idx, NULL) != 0 && idx <= indexB) {
+		Judy1Set(parray, idx, NULL);
+		n++;
+		if (idx == indexB) {
+			break;
+		}
+		idx++;
+	}
+	return n;
+}
+
+// Unset the indexes present from indexA to indexB (inclusive), found with Judy1First.
+// Return the number of indexes unset.
+static Word_t judy1UnsetRange(PPvoid_t parray, Word_t indexA, Word_t indexB) {
+	Word_t n = 0, idx = indexA;
+	while (Judy1First(*parray, &idx, NULL) != 0 && idx <= indexB) {
+		Judy1Unset(parray, idx, NULL);
+		n++;
+	}
+	return n;
+}
 */
 import "C"
 
 import (
 	"math"
+	"slices"
 	"unsafe"
 )
 
@@ -149,6 +204,68 @@ func (j *Judy1) Prev(index uint64) (uint64, bool) {
 	}
 }
 
+// Search (inclusive) for the first absent index that is equal to or greater than the passed index.
+// This is typically used to find the end of a run of consecutive indexes present, or a free slot.
+//
+//   index - search index
+//   returns uint64 - value of the first absent index that is equal to or greater than the passed index (only if bool return value is true)
+//           bool   - true if the search was successful, false if every index from index up is present
+func (j *Judy1) FirstEmpty(index uint64) (uint64, bool) {
+	var idx C.Word_t = C.Word_t(index)
+
+	if C.Judy1FirstEmpty(C.Pcvoid_t(j.array), &idx, nil) != 0 {
+		return uint64(idx), true
+	} else {
+		return 0, false
+	}
+}
+
+// Search (exclusive) for the first absent index that is greater than the passed index.
+//
+//   index - search index
+//   returns uint64 - value of the first absent index that is greater than the passed index (only if bool return value is true)
+//           bool   - true if the search was successful, false if every index above index is present
+func (j *Judy1) NextEmpty(index uint64) (uint64, bool) {
+	var idx C.Word_t = C.Word_t(index)
+
+	if C.Judy1NextEmpty(C.Pcvoid_t(j.array), &idx, nil) != 0 {
+		return uint64(idx), true
+	} else {
+		return 0, false
+	}
+}
+
+// Search (inclusive) for the last absent index that is equal to or less than the passed index.
+// This is typically used to find the start of a run of consecutive indexes present.
+//
+//   index - search index
+//   returns uint64 - value of the last absent index that is equal to or less than the passed index (only if bool return value is true)
+//           bool   - true if the search was successful, false if every index from index down is present
+func (j *Judy1) LastEmpty(index uint64) (uint64, bool) {
+	var idx C.Word_t = C.Word_t(index)
+
+	if C.Judy1LastEmpty(C.Pcvoid_t(j.array), &idx, nil) != 0 {
+		return uint64(idx), true
+	} else {
+		return 0, false
+	}
+}
+
+// Search (exclusive) for the last absent index that is less than the passed index.
+//
+//   index - search index
+//   returns uint64 - value of the last absent index that is less than the passed index (only if bool return value is true)
+//           bool   - true if the search was successful, false if every index below index is present
+func (j *Judy1) PrevEmpty(index uint64) (uint64, bool) {
+	var idx C.Word_t = C.Word_t(index)
+
+	if C.Judy1PrevEmpty(C.Pcvoid_t(j.array), &idx, nil) != 0 {
+		return uint64(idx), true
+	} else {
+		return 0, false
+	}
+}
+
 // Locate the Nth index that is present in the Judy1 array (Nth = 1 returns the first index present).
 //
 //   nth - nth index to find
@@ -163,3 +280,124 @@ func (j *Judy1) ByCount(nth uint64) (uint64, bool) {
 		return 0, false
 	}
 }
+
+// Return the rank of index in the Judy1 array, that is, the number of indexes present that are equal to or less
+// than index. Rank(math.MaxUint64) is the same as CountAll(). Rank(0) is 1 if index 0 is present and 0 otherwise.
+func (j *Judy1) Rank(index uint64) uint64 {
+	return j.CountFrom(0, index)
+}
+
+// Locate the kth index that is present in the Judy1 array, counting from zero (k = 0 returns the first index present).
+// This is the 0-based counterpart of ByCount, so Select(k) is the same as ByCount(k+1) and Select(Rank(i)-1) returns
+// i for any present index i.
+//
+//   k - 0-based position of the index to find
+//   returns uint64 - kth index (unless return false)
+//           bool   - true if the search was successful, false if k >= CountAll()
+func (j *Judy1) Select(k uint64) (uint64, bool) {
+	if k == math.MaxUint64 {
+		return 0, false
+	}
+	return j.ByCount(k + 1)
+}
+
+// Locate the index at quantile q of the indexes present in the Judy1 array, where q is in the range [0, 1].
+// The result is the index at 0-based position floor(q * (CountAll()-1)), so Quantile(0) is the first index present,
+// Quantile(1) is the last and no interpolation is done between indexes.
+//
+//   q - quantile in the range [0, 1]
+//   returns uint64 - index at quantile q (unless return false)
+//           bool   - true if the search was successful, false if the array is empty or q is outside [0, 1] (or NaN)
+func (j *Judy1) Quantile(q float64) (uint64, bool) {
+	k, ok := quantilePosition(j.CountAll(), q)
+	if !ok {
+		return 0, false
+	}
+	return j.Select(k)
+}
+
+// Locate the median index present in the Judy1 array. For an even number of indexes this is the lower of the two
+// middle indexes, which is the same as Quantile(0.5).
+//
+//   returns uint64 - median index (unless return false)
+//           bool   - true if the search was successful, false if the array is empty
+func (j *Judy1) Median() (uint64, bool) {
+	return j.Quantile(0.5)
+}
+
+// quantilePosition maps quantile q onto a 0-based position within n items.
+func quantilePosition(n uint64, q float64) (uint64, bool) {
+	if n == 0 || !(q >= 0 && q <= 1) {
+		return 0, false
+	}
+	k := uint64(q * float64(n-1))
+	if k > n-1 {
+		// float64 cannot represent every uint64, so the product may round up past the last position
+		k = n - 1
+	}
+	return k, true
+}
+
+// Append a page of up to limit indexes present in the Judy1 array to dst, starting with the index at 0-based position
+// offset, and return the extended slice. This is stable offset/limit paging for sorted index lists: the page is
+// located with ByCount and filled in a single call into the Judy library, so no Go-side Next loop is needed.
+// Fewer than limit indexes are appended when the end of the array is reached, and none when offset >= CountAll().
+//
+//   offset - 0-based position of the first index in the page
+//   limit  - maximum number of indexes to append
+//   dst    - slice the page is appended to (may be nil, or a previous page resliced to [:0] for reuse)
+func (j *Judy1) Page(offset uint64, limit int, dst []uint64) []uint64 {
+	count := j.CountAll()
+	if limit <= 0 || offset >= count {
+		return dst
+	}
+	limit = pageLimit(limit, count-offset)
+	dst = slices.Grow(dst, limit)
+	n := C.judy1PageByCount(C.Pcvoid_t(j.array), C.Word_t(offset+1), wordsAt(dst), C.size_t(limit))
+	return dst[:len(dst)+int(n)]
+}
+
+// Append a page of up to limit indexes present in the Judy1 array that are greater than index to dst, and return
+// the extended slice. This is keyset paging: pass the last index of the previous page to get the next one, which
+// stays correct while indexes are set and unset between pages. The first page is Page(0, limit, dst).
+// The page is filled in a single call into the Judy library.
+//
+//   index  - exclusive lower bound of the page, usually the last index of the previous page
+//   limit  - maximum number of indexes to append
+//   dst    - slice the page is appended to (may be nil, or a previous page resliced to [:0] for reuse)
+func (j *Judy1) PageAfter(index uint64, limit int, dst []uint64) []uint64 {
+	if limit <= 0 || index == math.MaxUint64 {
+		return dst
+	}
+	limit = pageLimit(limit, j.CountFrom(index+1, math.MaxUint64))
+	dst = slices.Grow(dst, limit)
+	n := C.judy1PageAfter(C.Pcvoid_t(j.array), C.Word_t(index), wordsAt(dst), C.size_t(limit))
+	return dst[:len(dst)+int(n)]
+}
+
+// setRange sets every index from indexA to indexB (inclusive) in a single call into C, jumping over the runs that
+// are already set.
+// Return the number of indexes set.
+func (j *Judy1) setRange(indexA, indexB uint64) uint64 {
+	return uint64(C.judy1SetRange(C.PPvoid_t(&j.array), C.Word_t(indexA), C.Word_t(indexB)))
+}
+
+// unsetRange unsets every index from indexA to indexB (inclusive) in a single call into C, visiting only the
+// indexes that are present.
+// Return the number of indexes unset.
+func (j *Judy1) unsetRange(indexA, indexB uint64) uint64 {
+	return uint64(C.judy1UnsetRange(C.PPvoid_t(&j.array), C.Word_t(indexA), C.Word_t(indexB)))
+}
+
+// pageLimit returns limit, or n if there are only n indexes left to page, so no more than needed is allocated.
+func pageLimit(limit int, n uint64) int {
+	if n < uint64(limit) {
+		return int(n)
+	}
+	return limit
+}
+
+// wordsAt returns a C pointer to the unused capacity of s, just past its last element.
+func wordsAt(s []uint64) *C.Word_t {
+	return (*C.Word_t)(unsafe.Pointer(unsafe.SliceData(s[len(s):cap(s)])))
+}
@@ -243,6 +243,290 @@ func TestJudy1Prev(t *testing.T) {
 
 }
 
+func TestJudy1Empty(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	if idx, ok := j.FirstEmpty(7); !ok || idx != 7 {
+		t.Errorf("FirstEmpty(7) on an empty array should be 7, was %v,%v", idx, ok)
+	}
+
+	var i uint64
+	for i = 10; i < 20; i++ {
+		j.Set(i)
+	}
+	j.Set(0)
+	j.Set(math.MaxUint64)
+
+	if idx, ok := j.FirstEmpty(10); !ok || idx != 20 {
+		t.Errorf("FirstEmpty(10) should be 20, was %v,%v", idx, ok)
+	}
+	if idx, ok := j.FirstEmpty(5); !ok || idx != 5 {
+		t.Errorf("FirstEmpty(5) should be 5, was %v,%v", idx, ok)
+	}
+	if idx, ok := j.NextEmpty(9); !ok || idx != 20 {
+		t.Errorf("NextEmpty(9) should be 20, was %v,%v", idx, ok)
+	}
+	if idx, ok := j.LastEmpty(19); !ok || idx != 9 {
+		t.Errorf("LastEmpty(19) should be 9, was %v,%v", idx, ok)
+	}
+	if idx, ok := j.PrevEmpty(20); !ok || idx != 9 {
+		t.Errorf("PrevEmpty(20) should be 9, was %v,%v", idx, ok)
+	}
+	if idx, ok := j.PrevEmpty(2); !ok || idx != 1 {
+		t.Errorf("PrevEmpty(2) should be 1, was %v,%v", idx, ok)
+	}
+	if _, ok := j.FirstEmpty(math.MaxUint64); ok {
+		t.Error("FirstEmpty(MaxUint64) should not be found")
+	}
+	if _, ok := j.LastEmpty(0); ok {
+		t.Error("LastEmpty(0) should not be found")
+	}
+	if _, ok := j.NextEmpty(math.MaxUint64); ok {
+		t.Error("NextEmpty(MaxUint64) should not be found")
+	}
+}
+
+func TestJudy1Rank(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	if r := j.Rank(math.MaxUint64); r != 0 {
+		t.Errorf("Rank of an empty array should be 0, was %v", r)
+	}
+
+	j.Set(0)
+	j.Set(10)
+	j.Set(20)
+	j.Set(math.MaxUint64)
+
+	if r := j.Rank(0); r != 1 {
+		t.Errorf("Rank(0) should be 1, was %v", r)
+	}
+	if r := j.Rank(15); r != 2 {
+		t.Errorf("Rank(15) should be 2, was %v", r)
+	}
+	if r := j.Rank(20); r != 3 {
+		t.Errorf("Rank(20) should be 3, was %v", r)
+	}
+	if r := j.Rank(math.MaxUint64 - 1); r != 3 {
+		t.Errorf("Rank(MaxUint64-1) should be 3, was %v", r)
+	}
+	if r := j.Rank(math.MaxUint64); r != 4 {
+		t.Errorf("Rank(MaxUint64) should be 4, was %v", r)
+	}
+
+	j.Unset(0)
+	if r := j.Rank(0); r != 0 {
+		t.Errorf("Rank(0) should be 0, was %v", r)
+	}
+}
+
+func TestJudy1Select(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	if _, ok := j.Select(0); ok {
+		t.Error("Select(0) of an empty array should not be found")
+	}
+
+	j.Set(0)
+	j.Set(10)
+	j.Set(math.MaxUint64)
+
+	if idx, ok := j.Select(0); !ok || idx != 0 {
+		t.Errorf("Select(0) should be 0,true was %v,%v", idx, ok)
+	}
+	if idx, ok := j.Select(1); !ok || idx != 10 {
+		t.Errorf("Select(1) should be 10,true was %v,%v", idx, ok)
+	}
+	if idx, ok := j.Select(2); !ok || idx != math.MaxUint64 {
+		t.Errorf("Select(2) should be MaxUint64,true was %v,%v", idx, ok)
+	}
+	if _, ok := j.Select(3); ok {
+		t.Error("Select(3) should not be found")
+	}
+	if _, ok := j.Select(math.MaxUint64); ok {
+		t.Error("Select(MaxUint64) should not be found")
+	}
+
+	for _, i := range []uint64{0, 10, math.MaxUint64} {
+		if idx, ok := j.Select(j.Rank(i) - 1); !ok || idx != i {
+			t.Errorf("Select(Rank(%v)-1) should be %v, was %v,%v", i, i, idx, ok)
+		}
+	}
+}
+
+func TestJudy1Quantile(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	if _, ok := j.Quantile(0.5); ok {
+		t.Error("Quantile of an empty array should not be found")
+	}
+	if _, ok := j.Median(); ok {
+		t.Error("Median of an empty array should not be found")
+	}
+
+	var i uint64
+	for i = 1; i <= 100; i++ {
+		j.Set(i * 10)
+	}
+
+	tests := []struct {
+		q   float64
+		idx uint64
+	}{
+		{0, 10},
+		{0.25, 250},
+		{0.5, 500},
+		{0.99, 990},
+		{1, 1000},
+	}
+	for _, tt := range tests {
+		if idx, ok := j.Quantile(tt.q); !ok || idx != tt.idx {
+			t.Errorf("Quantile(%v) should be %v,true was %v,%v", tt.q, tt.idx, idx, ok)
+		}
+	}
+	for _, q := range []float64{-0.1, 1.1, math.NaN(), math.Inf(1)} {
+		if _, ok := j.Quantile(q); ok {
+			t.Errorf("Quantile(%v) should not be found", q)
+		}
+	}
+
+	if idx, ok := j.Median(); !ok || idx != 500 {
+		t.Errorf("Median should be 500,true was %v,%v", idx, ok)
+	}
+	j.Set(math.MaxUint64)
+	if idx, ok := j.Median(); !ok || idx != 510 {
+		t.Errorf("Median should be 510,true was %v,%v", idx, ok)
+	}
+	if idx, ok := j.Quantile(1); !ok || idx != math.MaxUint64 {
+		t.Errorf("Quantile(1) should be MaxUint64,true was %v,%v", idx, ok)
+	}
+}
+
+func TestJudy1Page(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	if page := j.Page(0, 10, nil); len(page) != 0 {
+		t.Errorf("Page of an empty array should be empty, was %v", page)
+	}
+
+	var i uint64
+	for i = 0; i < 25; i++ {
+		j.Set(i * 2)
+	}
+
+	var all []uint64
+	var offset uint64
+	for {
+		page := j.Page(offset, 10, nil)
+		if len(page) == 0 {
+			break
+		}
+		all = append(all, page...)
+		offset += uint64(len(page))
+	}
+	if len(all) != 25 {
+		t.Fatalf("Paging should return 25 indexes, returned %v", len(all))
+	}
+	for i, idx := range all {
+		if idx != uint64(i*2) {
+			t.Errorf("Page index %v should be %v, was %v", i, i*2, idx)
+		}
+	}
+
+	if page := j.Page(20, 10, nil); len(page) != 5 || page[0] != 40 || page[4] != 48 {
+		t.Errorf("Page(20, 10) should be [40 .. 48], was %v", page)
+	}
+	if page := j.Page(25, 10, nil); len(page) != 0 {
+		t.Errorf("Page(25, 10) should be empty, was %v", page)
+	}
+	if page := j.Page(math.MaxUint64, 10, nil); len(page) != 0 {
+		t.Errorf("Page(MaxUint64, 10) should be empty, was %v", page)
+	}
+	if page := j.Page(0, 0, nil); len(page) != 0 {
+		t.Errorf("Page(0, 0) should be empty, was %v", page)
+	}
+	// a limit far larger than the array only allocates what is there
+	if page := j.Page(5, math.MaxInt, nil); len(page) != 20 || cap(page) > 100 {
+		t.Errorf("Page(5, MaxInt) should return 20 indexes, was %v with capacity %v", len(page), cap(page))
+	}
+
+	dst := []uint64{1, 2}
+	if page := j.Page(1, 2, dst); len(page) != 4 || page[0] != 1 || page[1] != 2 || page[2] != 2 || page[3] != 4 {
+		t.Errorf("Page should append to dst, was %v", page)
+	}
+}
+
+func TestJudy1PageAfter(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	var i uint64
+	for i = 0; i < 25; i++ {
+		j.Set(i * 2)
+	}
+	j.Set(math.MaxUint64)
+
+	page := j.Page(0, 10, nil)
+	var all []uint64
+	for len(page) > 0 {
+		all = append(all, page...)
+		page = j.PageAfter(page[len(page)-1], 10, page[:0])
+	}
+	if len(all) != 26 || all[0] != 0 || all[24] != 48 || all[25] != math.MaxUint64 {
+		t.Errorf("Keyset paging should return 0 .. 48, MaxUint64, was %v", all)
+	}
+
+	if page := j.PageAfter(47, 2, nil); len(page) != 2 || page[0] != 48 || page[1] != math.MaxUint64 {
+		t.Errorf("PageAfter(47, 2) should be [48 MaxUint64], was %v", page)
+	}
+	if page := j.PageAfter(47, math.MaxInt, nil); len(page) != 2 || cap(page) > 100 {
+		t.Errorf("PageAfter(47, MaxInt) should return 2 indexes, was %v with capacity %v", len(page), cap(page))
+	}
+	if page := j.PageAfter(math.MaxUint64, 10, nil); len(page) != 0 {
+		t.Errorf("PageAfter(MaxUint64, 10) should be empty, was %v", page)
+	}
+}
+
+func TestJudy1SetRange(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	j.Set(5)
+	j.Set(6)
+	if n := j.setRange(3, 9); n != 5 {
+		t.Errorf("setRange(3, 9) should set 5 indexes, set %v", n)
+	}
+	if ct := j.CountFrom(3, 9); ct != 7 || j.CountAll() != 7 {
+		t.Errorf("setRange(3, 9) should leave 3 .. 9 set, count was %v", ct)
+	}
+	if n := j.setRange(math.MaxUint64-1, math.MaxUint64); n != 2 {
+		t.Errorf("setRange at the top should set 2 indexes, set %v", n)
+	}
+
+	// unsetting the whole range only visits the indexes present
+	if n := j.unsetRange(4, math.MaxUint64); n != 8 {
+		t.Errorf("unsetRange(4, MaxUint64) should unset 8 indexes, unset %v", n)
+	}
+	if ct := j.CountAll(); ct != 1 || !j.Test(3) {
+		t.Errorf("Only 3 should be left, count was %v", ct)
+	}
+	if n := j.unsetRange(0, math.MaxUint64); n != 1 || j.CountAll() != 0 {
+		t.Errorf("unsetRange(0, MaxUint64) should unset 1 index, unset %v", n)
+	}
+}
+
 func runOrderedJudy1MemUsageTest(t *testing.T, n int) {
 	j := Judy1{}
 	defer j.Free()
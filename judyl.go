@@ -3,11 +3,54 @@ package judy
 /*
 #cgo LDFLAGS: -lJudy
 #include <Judy.h>
+
+// Fill keys and values with up to limit index/value pairs present in the array, starting with idx (pval must
+// point to its value).
+static size_t judyLFill(Pcvoid_t array, Word_t idx, PPvoid_t pval, Word_t *keys, Word_t *values, size_t limit) {
+	size_t n = 0;
+	for (;;) {
+		keys[n] = idx;
+		values[n++] = *(Word_t *)pval;
+		if (n == limit || (pval = JudyLNext(array, &idx, NULL)) == NULL) {
+			return n;
+		}
+	}
+}
+
+// Fill keys and values with up to limit index/value pairs present in the array, starting with the nth index present.
+static size_t judyLPageByCount(Pcvoid_t array, Word_t nth, Word_t *keys, Word_t *values, size_t limit) {
+	Word_t idx;
+	PPvoid_t pval = JudyLByCount(array, nth, &idx, NULL);
+	if (pval == NULL) {
+		return 0;
+	}
+	return judyLFill(array, idx, pval, keys, values, limit);
+}
+
+// Fill keys and values with up to limit index/value pairs present in the array whose index is greater than index.
+static size_t judyLPageAfter(Pcvoid_t array, Word_t index, Word_t *keys, Word_t *values, size_t limit) {
+	PPvoid_t pval = JudyLNext(array, &index, NULL);
+	if (pval == NULL) {
+		return 0;
+	}
+	return judyLFill(array, index, pval, keys, values, limit);
+}
+
+// Delete every index present between indexA and indexB (inclusive), and return the number deleted.
+static Word_t judyLDeleteRange(PPvoid_t parray, Word_t indexA, Word_t indexB) {
+	Word_t n = 0, idx = indexA;
+	while (JudyLFirst(*parray, &idx, NULL) != NULL && idx <= indexB) {
+		JudyLDel(parray, idx, NULL);
+		n++;
+	}
+	return n;
+}
 */
 import "C"
 
 import (
 	"math"
+	"slices"
 	"unsafe"
 )
 
@@ -48,6 +91,16 @@ func (j *JudyL) Delete(index uint64) bool {
 	return C.JudyLDel(C.PPvoid_t(&j.array), C.Word_t(index), nil) != 0
 }
 
+// Delete every Index/Value pair whose Index is between indexA and indexB (inclusive) from the JudyL array, in a
+// single call into the Judy library.
+// Return the number of pairs deleted.
+func (j *JudyL) DeleteRange(indexA, indexB uint64) uint64 {
+	if indexA > indexB {
+		return 0
+	}
+	return uint64(C.judyLDeleteRange(C.PPvoid_t(&j.array), C.Word_t(indexA), C.Word_t(indexB)))
+}
+
 // Get the Value associated with Index in the Judy array
 //   returns (value, true) if the index was found
 //   returns (_, false) if the index was not found
@@ -179,3 +232,133 @@ func (j *JudyL) ByCount(nth uint64) (uint64, uint64, bool) {
 		return uint64(idx), uint64(*((*C.Word_t)(pval))), true
 	}
 }
+
+// Return the rank of index in the JudyL array, that is, the number of indexes present that are equal to or less
+// than index. Rank(math.MaxUint64) is the same as CountAll(). Rank(0) is 1 if index 0 is present and 0 otherwise.
+func (j *JudyL) Rank(index uint64) uint64 {
+	return j.CountFrom(0, index)
+}
+
+// Locate the kth index that is present in the JudyL array, counting from zero (k = 0 returns the first index present).
+// This is the 0-based counterpart of ByCount, so Select(k) is the same as ByCount(k+1) and Select(Rank(i)-1) returns
+// i for any present index i.
+//
+//   k - 0-based position of the index to find
+//   returns uint64 - kth index (unless return false)
+//           uint64 - kth value (unless return false)
+//           bool   - true if the search was successful, false if k >= CountAll()
+func (j *JudyL) Select(k uint64) (uint64, uint64, bool) {
+	if k == math.MaxUint64 {
+		return 0, 0, false
+	}
+	return j.ByCount(k + 1)
+}
+
+// Locate the index at quantile q of the indexes present in the JudyL array, where q is in the range [0, 1].
+// The result is the index at 0-based position floor(q * (CountAll()-1)), so Quantile(0) is the first index present,
+// Quantile(1) is the last and no interpolation is done between indexes. Note that the quantile is taken over the
+// indexes, not the values.
+//
+//   q - quantile in the range [0, 1]
+//   returns uint64 - index at quantile q (unless return false)
+//           uint64 - value of that index (unless return false)
+//           bool   - true if the search was successful, false if the array is empty or q is outside [0, 1] (or NaN)
+func (j *JudyL) Quantile(q float64) (uint64, uint64, bool) {
+	k, ok := quantilePosition(j.CountAll(), q)
+	if !ok {
+		return 0, 0, false
+	}
+	return j.Select(k)
+}
+
+// Locate the median index present in the JudyL array. For an even number of indexes this is the lower of the two
+// middle indexes, which is the same as Quantile(0.5).
+//
+//   returns uint64 - median index (unless return false)
+//           uint64 - value of the median index (unless return false)
+//           bool   - true if the search was successful, false if the array is empty
+func (j *JudyL) Median() (uint64, uint64, bool) {
+	return j.Quantile(0.5)
+}
+
+// Append a page of up to limit index/value pairs present in the JudyL array to keys and values, starting with the
+// index at 0-based position offset, and return the extended slices. This is stable offset/limit paging over the
+// sorted indexes: the page is located with ByCount and filled in a single call into the Judy library.
+// Fewer than limit pairs are appended when the end of the array is reached, and none when offset >= CountAll().
+//
+//   offset - 0-based position of the first index in the page
+//   limit  - maximum number of index/value pairs to append
+//   keys   - slice the indexes are appended to (may be nil)
+//   values - slice the values are appended to (may be nil)
+func (j *JudyL) Page(offset uint64, limit int, keys, values []uint64) ([]uint64, []uint64) {
+	count := j.CountAll()
+	if limit <= 0 || offset >= count {
+		return keys, values
+	}
+	limit = pageLimit(limit, count-offset)
+	keys, values = slices.Grow(keys, limit), slices.Grow(values, limit)
+	n := int(C.judyLPageByCount(C.Pcvoid_t(j.array), C.Word_t(offset+1), wordsAt(keys), wordsAt(values), C.size_t(limit)))
+	return keys[:len(keys)+n], values[:len(values)+n]
+}
+
+// Append a page of up to limit index/value pairs present in the JudyL array whose index is greater than index to
+// keys and values, and return the extended slices. This is keyset paging: pass the last index of the previous page
+// to get the next one. The first page is Page(0, limit, keys, values).
+// The page is filled in a single call into the Judy library.
+//
+//   index  - exclusive lower bound of the page, usually the last index of the previous page
+//   limit  - maximum number of index/value pairs to append
+//   keys   - slice the indexes are appended to (may be nil)
+//   values - slice the values are appended to (may be nil)
+func (j *JudyL) PageAfter(index uint64, limit int, keys, values []uint64) ([]uint64, []uint64) {
+	if limit <= 0 || index == math.MaxUint64 {
+		return keys, values
+	}
+	limit = pageLimit(limit, j.CountFrom(index+1, math.MaxUint64))
+	keys, values = slices.Grow(keys, limit), slices.Grow(values, limit)
+	n := int(C.judyLPageAfter(C.Pcvoid_t(j.array), C.Word_t(index), wordsAt(keys), wordsAt(values), C.size_t(limit)))
+	return keys[:len(keys)+n], values[:len(values)+n]
+}
+
+// The slot helpers below return a pointer to the value word of an index, which is how nested Judy arrays are
+// built: a Judy1 or JudyL struct holds nothing but its root pointer, so a value word can be used in place as the
+// struct of a child array, e.g. (*Judy1)(j.insertSlot(index)). A slot pointer is only valid until the next
+// Insert or Delete on j, which may move the value words.
+
+// slot returns a pointer to the value word of index, or nil if index is not present.
+func (j *JudyL) slot(index uint64) unsafe.Pointer {
+	return unsafe.Pointer(C.JudyLGet(C.Pcvoid_t(j.array), C.Word_t(index), nil))
+}
+
+// insertSlot returns a pointer to the value word of index, inserting index with a zero value if it is not present.
+func (j *JudyL) insertSlot(index uint64) unsafe.Pointer {
+	return unsafe.Pointer(C.JudyLIns(C.PPvoid_t(&j.array), C.Word_t(index), nil))
+}
+
+// firstSlot is First, returning a pointer to the value word instead of the value (nil if not found).
+func (j *JudyL) firstSlot(index uint64) (uint64, unsafe.Pointer) {
+	idx := C.Word_t(index)
+	pval := unsafe.Pointer(C.JudyLFirst(C.Pcvoid_t(j.array), &idx, nil))
+	return uint64(idx), pval
+}
+
+// nextSlot is Next, returning a pointer to the value word instead of the value (nil if not found).
+func (j *JudyL) nextSlot(index uint64) (uint64, unsafe.Pointer) {
+	idx := C.Word_t(index)
+	pval := unsafe.Pointer(C.JudyLNext(C.Pcvoid_t(j.array), &idx, nil))
+	return uint64(idx), pval
+}
+
+// lastSlot is Last, returning a pointer to the value word instead of the value (nil if not found).
+func (j *JudyL) lastSlot(index uint64) (uint64, unsafe.Pointer) {
+	idx := C.Word_t(index)
+	pval := unsafe.Pointer(C.JudyLLast(C.Pcvoid_t(j.array), &idx, nil))
+	return uint64(idx), pval
+}
+
+// prevSlot is Prev, returning a pointer to the value word instead of the value (nil if not found).
+func (j *JudyL) prevSlot(index uint64) (uint64, unsafe.Pointer) {
+	idx := C.Word_t(index)
+	pval := unsafe.Pointer(C.JudyLPrev(C.Pcvoid_t(j.array), &idx, nil))
+	return uint64(idx), pval
+}
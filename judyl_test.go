@@ -127,6 +127,37 @@ func TestJudyLDelete(t *testing.T) {
 
 }
 
+func TestJudyLDeleteRange(t *testing.T) {
+
+	j := JudyL{}
+	defer j.Free()
+
+	var i uint64
+	for i = 0; i < 100; i++ {
+		j.Insert(i*10, i)
+	}
+	j.Insert(math.MaxUint64, 1)
+
+	if n := j.DeleteRange(15, 54); n != 4 {
+		t.Errorf("DeleteRange(15, 54) should delete 4 pairs, deleted %v", n)
+	}
+	if _, ok := j.Get(20); ok {
+		t.Error("20 should have been deleted")
+	}
+	if _, ok := j.Get(10); !ok {
+		t.Error("10 should not have been deleted")
+	}
+	if n := j.DeleteRange(60, 59); n != 0 {
+		t.Errorf("DeleteRange with indexA > indexB should delete nothing, deleted %v", n)
+	}
+	if n := j.DeleteRange(900, math.MaxUint64); n != 11 {
+		t.Errorf("DeleteRange(900, MaxUint64) should delete 11 pairs, deleted %v", n)
+	}
+	if ct := j.CountAll(); ct != 86 {
+		t.Errorf("Count should be 86, was %v", ct)
+	}
+}
+
 func TestJudyLFirst(t *testing.T) {
 
 	j := JudyL{}
@@ -206,7 +237,7 @@ func TestJudyLPrev(t *testing.T) {
 		t.Errorf("Prev(20) should be 18,9 was %v,%v", next, val)
 	}
 	if next, val, ok := j.Prev(21); ok && (next != 20 || val != 10) {
-		t.Errorf("Prev(21) should be 20,10 was %v", next, val)
+		t.Errorf("Prev(21) should be 20,10 was %v,%v", next, val)
 	}
 	if _, _, ok := j.Prev(2); ok {
 		t.Errorf("Prev(2) should not be found")
@@ -214,6 +245,160 @@ func TestJudyLPrev(t *testing.T) {
 
 }
 
+func TestJudyLRank(t *testing.T) {
+
+	j := JudyL{}
+	defer j.Free()
+
+	if r := j.Rank(math.MaxUint64); r != 0 {
+		t.Errorf("Rank of an empty array should be 0, was %v", r)
+	}
+
+	j.Insert(0, 1)
+	j.Insert(10, 2)
+	j.Insert(math.MaxUint64, 3)
+
+	if r := j.Rank(0); r != 1 {
+		t.Errorf("Rank(0) should be 1, was %v", r)
+	}
+	if r := j.Rank(9); r != 1 {
+		t.Errorf("Rank(9) should be 1, was %v", r)
+	}
+	if r := j.Rank(10); r != 2 {
+		t.Errorf("Rank(10) should be 2, was %v", r)
+	}
+	if r := j.Rank(math.MaxUint64); r != 3 {
+		t.Errorf("Rank(MaxUint64) should be 3, was %v", r)
+	}
+}
+
+func TestJudyLSelect(t *testing.T) {
+
+	j := JudyL{}
+	defer j.Free()
+
+	if _, _, ok := j.Select(0); ok {
+		t.Error("Select(0) of an empty array should not be found")
+	}
+
+	j.Insert(0, 1)
+	j.Insert(10, 2)
+	j.Insert(math.MaxUint64, 3)
+
+	if idx, val, ok := j.Select(0); !ok || idx != 0 || val != 1 {
+		t.Errorf("Select(0) should be 0,1,true was %v,%v,%v", idx, val, ok)
+	}
+	if idx, val, ok := j.Select(2); !ok || idx != math.MaxUint64 || val != 3 {
+		t.Errorf("Select(2) should be MaxUint64,3,true was %v,%v,%v", idx, val, ok)
+	}
+	if _, _, ok := j.Select(3); ok {
+		t.Error("Select(3) should not be found")
+	}
+	if _, _, ok := j.Select(math.MaxUint64); ok {
+		t.Error("Select(MaxUint64) should not be found")
+	}
+}
+
+func TestJudyLQuantile(t *testing.T) {
+
+	j := JudyL{}
+	defer j.Free()
+
+	if _, _, ok := j.Median(); ok {
+		t.Error("Median of an empty array should not be found")
+	}
+
+	var i uint64
+	for i = 1; i <= 100; i++ {
+		j.Insert(i*10, i)
+	}
+
+	if idx, val, ok := j.Quantile(0); !ok || idx != 10 || val != 1 {
+		t.Errorf("Quantile(0) should be 10,1,true was %v,%v,%v", idx, val, ok)
+	}
+	if idx, val, ok := j.Quantile(1); !ok || idx != 1000 || val != 100 {
+		t.Errorf("Quantile(1) should be 1000,100,true was %v,%v,%v", idx, val, ok)
+	}
+	if idx, val, ok := j.Median(); !ok || idx != 500 || val != 50 {
+		t.Errorf("Median should be 500,50,true was %v,%v,%v", idx, val, ok)
+	}
+	if _, _, ok := j.Quantile(2); ok {
+		t.Error("Quantile(2) should not be found")
+	}
+}
+
+func TestJudyLPage(t *testing.T) {
+
+	j := JudyL{}
+	defer j.Free()
+
+	if keys, values := j.Page(0, 10, nil, nil); len(keys) != 0 || len(values) != 0 {
+		t.Errorf("Page of an empty array should be empty, was %v, %v", keys, values)
+	}
+
+	var i uint64
+	for i = 0; i < 25; i++ {
+		j.Insert(i*2, i)
+	}
+
+	keys, values := j.Page(20, 10, nil, nil)
+	if len(keys) != 5 || len(values) != 5 {
+		t.Fatalf("Page(20, 10) should return 5 pairs, was %v, %v", keys, values)
+	}
+	for n := range keys {
+		if keys[n] != uint64(40+n*2) || values[n] != uint64(20+n) {
+			t.Errorf("Page pair %v should be %v,%v was %v,%v", n, 40+n*2, 20+n, keys[n], values[n])
+		}
+	}
+
+	if keys, values := j.Page(25, 10, nil, nil); len(keys) != 0 || len(values) != 0 {
+		t.Errorf("Page(25, 10) should be empty, was %v, %v", keys, values)
+	}
+	if keys, _ := j.Page(math.MaxUint64, 10, nil, nil); len(keys) != 0 {
+		t.Errorf("Page(MaxUint64, 10) should be empty, was %v", keys)
+	}
+	// a limit far larger than the array only allocates what is there
+	if keys, values := j.Page(5, math.MaxInt, nil, nil); len(keys) != 20 || cap(keys) > 100 || cap(values) > 100 {
+		t.Errorf("Page(5, MaxInt) should return 20 pairs, was %v with capacity %v", len(keys), cap(keys))
+	}
+}
+
+func TestJudyLPageAfter(t *testing.T) {
+
+	j := JudyL{}
+	defer j.Free()
+
+	var i uint64
+	for i = 0; i < 25; i++ {
+		j.Insert(i*2, i)
+	}
+
+	keys, values := j.Page(0, 10, nil, nil)
+	var ct int
+	for len(keys) > 0 {
+		for n := range keys {
+			if values[n] != keys[n]/2 {
+				t.Errorf("Value of %v should be %v, was %v", keys[n], keys[n]/2, values[n])
+			}
+		}
+		ct += len(keys)
+		keys, values = j.PageAfter(keys[len(keys)-1], 10, keys[:0], values[:0])
+	}
+	if ct != 25 {
+		t.Errorf("Keyset paging should return 25 pairs, returned %v", ct)
+	}
+
+	if keys, values := j.PageAfter(45, 10, nil, nil); len(keys) != 2 || keys[0] != 46 || values[1] != 24 {
+		t.Errorf("PageAfter(45, 10) should be [46 48] [23 24], was %v, %v", keys, values)
+	}
+	if keys, values := j.PageAfter(45, math.MaxInt, nil, nil); len(keys) != 2 || cap(keys) > 100 || cap(values) > 100 {
+		t.Errorf("PageAfter(45, MaxInt) should return 2 pairs, was %v with capacity %v", len(keys), cap(keys))
+	}
+	if keys, _ := j.PageAfter(math.MaxUint64, 10, nil, nil); len(keys) != 0 {
+		t.Errorf("PageAfter(MaxUint64, 10) should be empty, was %v", keys)
+	}
+}
+
 func runOrderedJudyLMemUsageTest(t *testing.T, n int) {
 	j := JudyL{}
 	defer j.Free()
@@ -0,0 +1,364 @@
+package judy
+
+import (
+	"encoding/binary"
+	"iter"
+	"math"
+)
+
+// A Key128 is a 128-bit key, such as a UUID, split into its high and low 64-bit words.
+// Keys are ordered by Hi, then by Lo, which is the same as the byte order of the big-endian form (see Bytes).
+type Key128 struct {
+	Hi, Lo uint64
+}
+
+// Key128FromBytes returns the key for the big-endian 16 bytes b, such as the bytes of a UUID.
+func Key128FromBytes(b [16]byte) Key128 {
+	return Key128{Hi: binary.BigEndian.Uint64(b[:8]), Lo: binary.BigEndian.Uint64(b[8:])}
+}
+
+// Bytes returns the big-endian 16 bytes of the key.
+func (k Key128) Bytes() [16]byte {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], k.Hi)
+	binary.BigEndian.PutUint64(b[8:], k.Lo)
+	return b
+}
+
+// Compare returns -1, 0 or +1 depending on whether k is less than, equal to or greater than o.
+func (k Key128) Compare(o Key128) int {
+	switch {
+	case k.Hi < o.Hi || k.Hi == o.Hi && k.Lo < o.Lo:
+		return -1
+	case k == o:
+		return 0
+	}
+	return 1
+}
+
+// maxKey128 is the largest Key128.
+var maxKey128 = Key128{Hi: math.MaxUint64, Lo: math.MaxUint64}
+
+// A Set128 is a set of 128-bit keys, built as a two-level Judy array: a JudyL indexed by the high word of the key
+// whose values are Judy1 arrays of the low words. Keys iterate in Key128 order.
+// The default value of this struct is a valid empty set.
+//
+//    s := Set128{}
+//    defer s.Free()
+//
+//    s.Set(Key128FromBytes(uuid))
+//
+// NOTE: The Judy arrays allocate memory outside of the Go runtime. It is very important that you call Free() on a
+// Set128 after using it to prevent memory leaks; Free releases every sub-array as well.
+type Set128 struct {
+	top JudyLOfJudy1
+}
+
+// sub returns the Judy1 array of low words for hi, or nil if there is none.
+func (s *Set128) sub(hi uint64) *Judy1 {
+	return s.top.Get(hi)
+}
+
+// Set key in the set.
+// Return true if key was previously absent, otherwise false.
+func (s *Set128) Set(key Key128) bool {
+	return s.top.GetOrCreate(key.Hi).Set(key.Lo)
+}
+
+// Unset key in the set. A sub-array that becomes empty is removed.
+// Return true if key was previously present, otherwise false.
+func (s *Set128) Unset(key Key128) bool {
+	sub := s.sub(key.Hi)
+	if sub == nil || !sub.Unset(key.Lo) {
+		return false
+	}
+	if sub.array == nil {
+		s.top.Delete(key.Hi)
+	}
+	return true
+}
+
+// Test if key is present in the set.
+func (s *Set128) Test(key Key128) bool {
+	sub := s.sub(key.Hi)
+	return sub != nil && sub.Test(key.Lo)
+}
+
+// Count the number of keys present in the set.
+func (s *Set128) CountAll() uint64 {
+	return s.CountFrom(Key128{}, maxKey128)
+}
+
+// Count the number of keys present in the set between keyA and keyB (inclusive).
+// The count is made of CountFrom on the sub-arrays at either end and CountAll on every sub-array in between, so it
+// does not scan the keys.
+func (s *Set128) CountFrom(keyA, keyB Key128) uint64 {
+	if keyA.Compare(keyB) > 0 {
+		return 0
+	}
+	if keyA.Hi == keyB.Hi {
+		if sub := s.sub(keyA.Hi); sub != nil {
+			return sub.CountFrom(keyA.Lo, keyB.Lo)
+		}
+		return 0
+	}
+
+	var ct uint64
+	if sub := s.sub(keyA.Hi); sub != nil {
+		ct += sub.CountFrom(keyA.Lo, math.MaxUint64)
+	}
+	for hi, sub, ok := s.top.Next(keyA.Hi); ok && hi < keyB.Hi; hi, sub, ok = s.top.Next(hi) {
+		ct += sub.CountAll()
+	}
+	if sub := s.sub(keyB.Hi); sub != nil {
+		ct += sub.CountFrom(0, keyB.Lo)
+	}
+	return ct
+}
+
+// Search (inclusive) for the first key present that is equal to or greater than the passed key.
+func (s *Set128) First(key Key128) (Key128, bool) {
+	if sub := s.sub(key.Hi); sub != nil {
+		if lo, ok := sub.First(key.Lo); ok {
+			return Key128{key.Hi, lo}, true
+		}
+	}
+	hi, sub, ok := s.top.Next(key.Hi)
+	if !ok {
+		return Key128{}, false
+	}
+	lo, _ := sub.First(0)
+	return Key128{hi, lo}, true
+}
+
+// Search (exclusive) for the first key present that is greater than the passed key.
+func (s *Set128) Next(key Key128) (Key128, bool) {
+	switch {
+	case key.Lo < math.MaxUint64:
+		return s.First(Key128{key.Hi, key.Lo + 1})
+	case key.Hi < math.MaxUint64:
+		return s.First(Key128{key.Hi + 1, 0})
+	}
+	return Key128{}, false
+}
+
+// Search (inclusive) for the last key present that is equal to or less than the passed key.
+func (s *Set128) Last(key Key128) (Key128, bool) {
+	if sub := s.sub(key.Hi); sub != nil {
+		if lo, ok := sub.Last(key.Lo); ok {
+			return Key128{key.Hi, lo}, true
+		}
+	}
+	hi, sub, ok := s.top.Prev(key.Hi)
+	if !ok {
+		return Key128{}, false
+	}
+	lo, _ := sub.Last(math.MaxUint64)
+	return Key128{hi, lo}, true
+}
+
+// Search (exclusive) for the last key present that is less than the passed key.
+func (s *Set128) Prev(key Key128) (Key128, bool) {
+	switch {
+	case key.Lo > 0:
+		return s.Last(Key128{key.Hi, key.Lo - 1})
+	case key.Hi > 0:
+		return s.Last(Key128{key.Hi - 1, math.MaxUint64})
+	}
+	return Key128{}, false
+}
+
+// Locate the Nth key that is present in the set (Nth = 1 returns the smallest key). The sub-arrays before the one
+// holding it are counted one at a time, so the cost grows with the number of distinct high words before the key.
+func (s *Set128) ByCount(nth uint64) (Key128, bool) {
+	if nth == 0 {
+		return Key128{}, false
+	}
+	for hi, sub, ok := s.top.First(0); ok; hi, sub, ok = s.top.Next(hi) {
+		ct := sub.CountAll()
+		if nth <= ct {
+			lo, _ := sub.ByCount(nth)
+			return Key128{hi, lo}, true
+		}
+		nth -= ct
+	}
+	return Key128{}, false
+}
+
+// All returns an iterator over the keys present in the set, in ascending order. Each step resumes with Next, so
+// keys may be set and unset while iterating.
+func (s *Set128) All() iter.Seq[Key128] {
+	return func(yield func(Key128) bool) {
+		for key, ok := s.First(Key128{}); ok; key, ok = s.Next(key) {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Return the number of bytes of memory currently in use by the set and all of its sub-arrays.
+func (s *Set128) MemoryUsed() uint64 {
+	return s.top.MemoryUsed()
+}
+
+// Free the entire set, including every sub-array.
+// Return the number of bytes freed.
+func (s *Set128) Free() uint64 {
+	return s.top.Free()
+}
+
+// A Map128 is a map of 128-bit keys to uint64 values, built as a two-level Judy array: a JudyL indexed by the high
+// word of the key whose values are JudyL arrays of the low words. Keys iterate in Key128 order.
+// The default value of this struct is a valid empty map.
+//
+//    m := Map128{}
+//    defer m.Free()
+//
+//    m.Insert(Key128FromBytes(uuid), 42)
+//
+// NOTE: The Judy arrays allocate memory outside of the Go runtime. It is very important that you call Free() on a
+// Map128 after using it to prevent memory leaks; Free releases every sub-array as well.
+type Map128 struct {
+	top JudyLOfJudyL
+}
+
+// sub returns the JudyL array of low words for hi, or nil if there is none.
+func (m *Map128) sub(hi uint64) *JudyL {
+	return m.top.Get(hi)
+}
+
+// Insert a Key and Value into the map. If the Key was already present, the current Value is replaced.
+func (m *Map128) Insert(key Key128, value uint64) {
+	m.top.GetOrCreate(key.Hi).Insert(key.Lo, value)
+}
+
+// Delete the Key/Value pair from the map. A sub-array that becomes empty is removed.
+// Returns true if successful. Returns false if Key was not present.
+func (m *Map128) Delete(key Key128) bool {
+	sub := m.sub(key.Hi)
+	if sub == nil || !sub.Delete(key.Lo) {
+		return false
+	}
+	if sub.array == nil {
+		m.top.Delete(key.Hi)
+	}
+	return true
+}
+
+// Get the Value associated with Key in the map
+//   returns (value, true) if the key was found
+//   returns (_, false) if the key was not found
+func (m *Map128) Get(key Key128) (uint64, bool) {
+	if sub := m.sub(key.Hi); sub != nil {
+		return sub.Get(key.Lo)
+	}
+	return 0, false
+}
+
+// Count the number of keys present in the map.
+func (m *Map128) CountAll() uint64 {
+	return m.CountFrom(Key128{}, maxKey128)
+}
+
+// Count the number of keys present in the map between keyA and keyB (inclusive).
+// The count is made of CountFrom on the sub-arrays at either end and CountAll on every sub-array in between, so it
+// does not scan the keys.
+func (m *Map128) CountFrom(keyA, keyB Key128) uint64 {
+	if keyA.Compare(keyB) > 0 {
+		return 0
+	}
+	if keyA.Hi == keyB.Hi {
+		if sub := m.sub(keyA.Hi); sub != nil {
+			return sub.CountFrom(keyA.Lo, keyB.Lo)
+		}
+		return 0
+	}
+
+	var ct uint64
+	if sub := m.sub(keyA.Hi); sub != nil {
+		ct += sub.CountFrom(keyA.Lo, math.MaxUint64)
+	}
+	for hi, sub, ok := m.top.Next(keyA.Hi); ok && hi < keyB.Hi; hi, sub, ok = m.top.Next(hi) {
+		ct += sub.CountAll()
+	}
+	if sub := m.sub(keyB.Hi); sub != nil {
+		ct += sub.CountFrom(0, keyB.Lo)
+	}
+	return ct
+}
+
+// Search (inclusive) for the first key present that is equal to or greater than the passed key.
+func (m *Map128) First(key Key128) (Key128, uint64, bool) {
+	if sub := m.sub(key.Hi); sub != nil {
+		if lo, val, ok := sub.First(key.Lo); ok {
+			return Key128{key.Hi, lo}, val, true
+		}
+	}
+	hi, sub, ok := m.top.Next(key.Hi)
+	if !ok {
+		return Key128{}, 0, false
+	}
+	lo, val, _ := sub.First(0)
+	return Key128{hi, lo}, val, true
+}
+
+// Search (exclusive) for the first key present that is greater than the passed key.
+func (m *Map128) Next(key Key128) (Key128, uint64, bool) {
+	switch {
+	case key.Lo < math.MaxUint64:
+		return m.First(Key128{key.Hi, key.Lo + 1})
+	case key.Hi < math.MaxUint64:
+		return m.First(Key128{key.Hi + 1, 0})
+	}
+	return Key128{}, 0, false
+}
+
+// Search (inclusive) for the last key present that is equal to or less than the passed key.
+func (m *Map128) Last(key Key128) (Key128, uint64, bool) {
+	if sub := m.sub(key.Hi); sub != nil {
+		if lo, val, ok := sub.Last(key.Lo); ok {
+			return Key128{key.Hi, lo}, val, true
+		}
+	}
+	hi, sub, ok := m.top.Prev(key.Hi)
+	if !ok {
+		return Key128{}, 0, false
+	}
+	lo, val, _ := sub.Last(math.MaxUint64)
+	return Key128{hi, lo}, val, true
+}
+
+// Search (exclusive) for the last key present that is less than the passed key.
+func (m *Map128) Prev(key Key128) (Key128, uint64, bool) {
+	switch {
+	case key.Lo > 0:
+		return m.Last(Key128{key.Hi, key.Lo - 1})
+	case key.Hi > 0:
+		return m.Last(Key128{key.Hi - 1, math.MaxUint64})
+	}
+	return Key128{}, 0, false
+}
+
+// All returns an iterator over the key/value pairs in the map, in ascending key order. Each step resumes with Next,
+// so keys may be inserted and deleted while iterating.
+func (m *Map128) All() iter.Seq2[Key128, uint64] {
+	return func(yield func(Key128, uint64) bool) {
+		for key, val, ok := m.First(Key128{}); ok; key, val, ok = m.Next(key) {
+			if !yield(key, val) {
+				return
+			}
+		}
+	}
+}
+
+// Return the number of bytes of memory currently in use by the map and all of its sub-arrays.
+func (m *Map128) MemoryUsed() uint64 {
+	return m.top.MemoryUsed()
+}
+
+// Free the entire map, including every sub-array.
+// Return the number of bytes freed.
+func (m *Map128) Free() uint64 {
+	return m.top.Free()
+}
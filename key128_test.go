@@ -0,0 +1,208 @@
+package judy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKey128(t *testing.T) {
+
+	b := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	k := Key128FromBytes(b)
+	if k.Hi != 0x123e4567e89b12d3 || k.Lo != 0xa456426614174000 {
+		t.Errorf("Key should be big-endian, was %x %x", k.Hi, k.Lo)
+	}
+	if k.Bytes() != b {
+		t.Errorf("Bytes should round-trip, was %x", k.Bytes())
+	}
+
+	if (Key128{1, math.MaxUint64}).Compare(Key128{2, 0}) != -1 || (Key128{2, 0}).Compare(Key128{1, 5}) != 1 || (Key128{3, 4}).Compare(Key128{3, 4}) != 0 {
+		t.Error("Compare should order by Hi, then Lo")
+	}
+}
+
+func newSet128TestKeys() []Key128 {
+	return []Key128{
+		{0, 0}, {0, 5}, {0, math.MaxUint64},
+		{7, 1}, {7, 2},
+		{100, 0},
+		{math.MaxUint64, math.MaxUint64},
+	}
+}
+
+func TestSet128(t *testing.T) {
+
+	s := Set128{}
+	defer s.Free()
+
+	if _, ok := s.First(Key128{}); ok {
+		t.Error("First of an empty set should not be found")
+	}
+
+	keys := newSet128TestKeys()
+	for n := len(keys) - 1; n >= 0; n-- {
+		if !s.Set(keys[n]) {
+			t.Errorf("Set(%v) should succeed", keys[n])
+		}
+	}
+	if s.Set(keys[0]) {
+		t.Error("Set of a present key should fail")
+	}
+
+	if ct := s.CountAll(); ct != 7 {
+		t.Errorf("Count should be 7, was %v", ct)
+	}
+	if !s.Test(Key128{7, 2}) || s.Test(Key128{7, 3}) || s.Test(Key128{8, 2}) {
+		t.Error("Test should only find present keys")
+	}
+
+	var n int
+	for k := range s.All() {
+		if k != keys[n] {
+			t.Errorf("Key %v should be %v, was %v", n, keys[n], k)
+		}
+		n++
+	}
+	if n != len(keys) {
+		t.Errorf("All should return %v keys, returned %v", len(keys), n)
+	}
+
+	if k, ok := s.First(Key128{0, 6}); !ok || k != (Key128{0, math.MaxUint64}) {
+		t.Errorf("First({0 6}) should be {0 Max}, was %v,%v", k, ok)
+	}
+	if k, ok := s.First(Key128{1, 0}); !ok || k != (Key128{7, 1}) {
+		t.Errorf("First({1 0}) should be {7 1}, was %v,%v", k, ok)
+	}
+	if k, ok := s.Next(Key128{0, math.MaxUint64}); !ok || k != (Key128{7, 1}) {
+		t.Errorf("Next({0 Max}) should be {7 1}, was %v,%v", k, ok)
+	}
+	if _, ok := s.Next(Key128{math.MaxUint64, math.MaxUint64}); ok {
+		t.Error("Next(max) should not be found")
+	}
+	if k, ok := s.Last(Key128{50, 0}); !ok || k != (Key128{7, 2}) {
+		t.Errorf("Last({50 0}) should be {7 2}, was %v,%v", k, ok)
+	}
+	if k, ok := s.Prev(Key128{7, 1}); !ok || k != (Key128{0, math.MaxUint64}) {
+		t.Errorf("Prev({7 1}) should be {0 Max}, was %v,%v", k, ok)
+	}
+	if _, ok := s.Prev(Key128{0, 0}); ok {
+		t.Error("Prev({0 0}) should not be found")
+	}
+
+	if ct := s.CountFrom(Key128{0, 1}, Key128{100, 0}); ct != 5 {
+		t.Errorf("CountFrom({0 1}, {100 0}) should be 5, was %v", ct)
+	}
+	if ct := s.CountFrom(Key128{7, 0}, Key128{7, 1}); ct != 1 {
+		t.Errorf("CountFrom({7 0}, {7 1}) should be 1, was %v", ct)
+	}
+	if ct := s.CountFrom(Key128{100, 0}, Key128{7, 0}); ct != 0 {
+		t.Errorf("CountFrom of a reversed range should be 0, was %v", ct)
+	}
+
+	if !s.Unset(Key128{100, 0}) || s.Unset(Key128{100, 0}) || s.Unset(Key128{101, 0}) {
+		t.Error("Unset({100 0}) should succeed once")
+	}
+	if s.top.CountAll() != 3 {
+		t.Errorf("The empty sub-array should be removed, %v remain", s.top.CountAll())
+	}
+	if s.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
+
+func TestSet128ByCount(t *testing.T) {
+
+	s := Set128{}
+	defer s.Free()
+
+	keys := newSet128TestKeys()
+	for _, k := range keys {
+		s.Set(k)
+	}
+	for n, k := range keys {
+		if got, ok := s.ByCount(uint64(n + 1)); !ok || got != k {
+			t.Errorf("ByCount(%v) should be %v, was %v,%v", n+1, k, got, ok)
+		}
+	}
+	if _, ok := s.ByCount(0); ok {
+		t.Error("ByCount(0) should not be found")
+	}
+	if _, ok := s.ByCount(uint64(len(keys) + 1)); ok {
+		t.Error("ByCount past the last key should not be found")
+	}
+}
+
+func TestSet128Free(t *testing.T) {
+
+	s := Set128{}
+	for _, k := range newSet128TestKeys() {
+		s.Set(k)
+	}
+	if r := s.Free(); r == 0 {
+		t.Error("Free should return the bytes freed")
+	}
+	if ct := s.CountAll(); ct != 0 {
+		t.Errorf("Count should be 0 after Free, was %v", ct)
+	}
+}
+
+func TestMap128(t *testing.T) {
+
+	m := Map128{}
+	defer m.Free()
+
+	keys := newSet128TestKeys()
+	for n, k := range keys {
+		m.Insert(k, uint64(n))
+	}
+	m.Insert(Key128{7, 2}, 40)
+
+	if ct := m.CountAll(); ct != 7 {
+		t.Errorf("Count should be 7, was %v", ct)
+	}
+	if v, ok := m.Get(Key128{7, 2}); !ok || v != 40 {
+		t.Errorf("Get({7 2}) should be 40, was %v,%v", v, ok)
+	}
+	if _, ok := m.Get(Key128{8, 2}); ok {
+		t.Error("Get({8 2}) should not be found")
+	}
+
+	var n int
+	for k, v := range m.All() {
+		if k != keys[n] || (v != uint64(n) && k != (Key128{7, 2})) {
+			t.Errorf("Pair %v should be %v,%v was %v,%v", n, keys[n], n, k, v)
+		}
+		n++
+	}
+
+	if k, v, ok := m.Next(Key128{7, 2}); !ok || k != (Key128{100, 0}) || v != 5 {
+		t.Errorf("Next({7 2}) should be {100 0},5 was %v,%v,%v", k, v, ok)
+	}
+	if k, v, ok := m.Prev(Key128{100, 0}); !ok || k != (Key128{7, 2}) || v != 40 {
+		t.Errorf("Prev({100 0}) should be {7 2},40 was %v,%v,%v", k, v, ok)
+	}
+	if k, _, ok := m.Last(maxKey128); !ok || k != maxKey128 {
+		t.Errorf("Last(max) should be max, was %v,%v", k, ok)
+	}
+	if ct := m.CountFrom(Key128{0, 5}, Key128{7, 1}); ct != 3 {
+		t.Errorf("CountFrom({0 5}, {7 1}) should be 3, was %v", ct)
+	}
+
+	for k := range m.All() {
+		if k.Hi == 0 {
+			m.Delete(k)
+		}
+	}
+	if ct := m.CountAll(); ct != 4 {
+		t.Errorf("Deleting while iterating should leave 4 keys, count was %v", ct)
+	}
+	if _, _, ok := m.First(Key128{}); !ok {
+		t.Error("First should be found")
+	}
+	if m.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+	if m.Free() == 0 || m.CountAll() != 0 {
+		t.Error("Free should release every sub-array")
+	}
+}
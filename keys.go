@@ -0,0 +1,395 @@
+package judy
+
+import (
+	"iter"
+	"math"
+	"time"
+)
+
+// A KeyCodec converts keys of type K to and from uint64 indexes while preserving their order: for any keys a < b,
+// EncodeKey(a) < EncodeKey(b). Judy arrays keep indexes in unsigned order, so storing keys through a KeyCodec makes
+// First, Next, ByCount and CountFrom follow the natural order of K. DecodeKey(EncodeKey(k)) must return k.
+//
+// Note that the value codecs such as Int64Codec do not preserve order and must not be used as a KeyCodec.
+type KeyCodec[K any] interface {
+	EncodeKey(k K) uint64
+	DecodeKey(index uint64) K
+}
+
+// Int64Key orders int64 keys by flipping the sign bit, so math.MinInt64 encodes as 0 and math.MaxInt64 encodes as
+// math.MaxUint64.
+type Int64Key struct{}
+
+func (Int64Key) EncodeKey(k int64) uint64     { return uint64(k) ^ (1 << 63) }
+func (Int64Key) DecodeKey(index uint64) int64 { return int64(index ^ (1 << 63)) }
+
+// Float64Key orders float64 keys by their IEEE 754 total order: negative numbers have all bits flipped and positive
+// numbers have the sign bit flipped. -Inf sorts below every finite number and +Inf above, and -0 sorts just below +0.
+// NaNs sort below -Inf or above +Inf depending on their sign bit. Every value round-trips bit for bit.
+type Float64Key struct{}
+
+func (Float64Key) EncodeKey(k float64) uint64 {
+	b := math.Float64bits(k)
+	if b>>63 != 0 {
+		return ^b
+	}
+	return b | 1<<63
+}
+
+func (Float64Key) DecodeKey(index uint64) float64 {
+	if index>>63 != 0 {
+		return math.Float64frombits(index &^ (1 << 63))
+	}
+	return math.Float64frombits(^index)
+}
+
+// TimeKey orders time.Time keys by instant, encoded as nanoseconds since the Unix epoch with the sign bit flipped.
+// As with TimeCodec, the years 1678 to 2262 are covered, times decode in UTC and EncodeKey panics for times outside
+// that range. Such times can still be passed as query bounds to a TimeSet or TimeMap, as in
+// First(time.Time{}), where they sort before or after every time that can be stored.
+type TimeKey struct{}
+
+func (TimeKey) EncodeKey(k time.Time) uint64 {
+	return Int64Key{}.EncodeKey(int64(TimeCodec{}.Encode(k)))
+}
+
+func (TimeKey) DecodeKey(index uint64) time.Time {
+	return TimeCodec{}.Decode(uint64(Int64Key{}.DecodeKey(index)))
+}
+
+func (TimeKey) clampKey(k time.Time) (uint64, int) {
+	switch {
+	case k.Before(minUnixNanoTime):
+		return 0, -1
+	case k.After(maxUnixNanoTime):
+		return math.MaxUint64, 1
+	}
+	return TimeKey{}.EncodeKey(k), 0
+}
+
+// A keyClamper is a KeyCodec that cannot encode every key of type K. clampKey returns the index of a key it can
+// encode with side 0, or the first or last index with side -1 or 1 for a key that sorts below or above every key it
+// can encode.
+type keyClamper[K any] interface {
+	clampKey(k K) (index uint64, side int)
+}
+
+// queryKey encodes a key passed to a query with codec. Unlike EncodeKey, it does not fail on a key that codec
+// cannot encode; such a key cannot be present, and is clamped as by keyClamper.
+func queryKey[K any, C KeyCodec[K]](codec C, key K) (uint64, int) {
+	if c, ok := any(codec).(keyClamper[K]); ok {
+		return c.clampKey(key)
+	}
+	return codec.EncodeKey(key), 0
+}
+
+// DurationKey orders time.Duration keys, negative durations first.
+type DurationKey struct{}
+
+func (DurationKey) EncodeKey(k time.Duration) uint64 {
+	return Int64Key{}.EncodeKey(int64(k))
+}
+
+func (DurationKey) DecodeKey(index uint64) time.Duration {
+	return time.Duration(Int64Key{}.DecodeKey(index))
+}
+
+// Int64Set is a set of int64 keys in numeric order.
+type Int64Set = KeySet[int64, Int64Key]
+
+// Float64Set is a set of float64 keys in numeric order.
+type Float64Set = KeySet[float64, Float64Key]
+
+// TimeSet is a set of time.Time keys in chronological order.
+type TimeSet = KeySet[time.Time, TimeKey]
+
+// Int64Map is a map of int64 keys in numeric order to uint64 values.
+type Int64Map = KeyMap[int64, Int64Key]
+
+// Float64Map is a map of float64 keys in numeric order to uint64 values.
+type Float64Map = KeyMap[float64, Float64Key]
+
+// TimeMap is a map of time.Time keys in chronological order to uint64 values.
+type TimeMap = KeyMap[time.Time, TimeKey]
+
+// A KeySet is a Judy1 array of keys of type K, stored as indexes through the order-preserving KeyCodec C.
+// Iteration and range queries follow the order of K.
+// The default value of this struct is a valid empty set.
+//
+//    s := Int64Set{}
+//    defer s.Free()
+//
+//    s.Set(-5)
+//    s.Set(3)
+//    s.CountFrom(-10, 0) // returns 1
+//
+// NOTE: The underlying Judy1 array allocates memory outside of the Go runtime. It is very important that you call
+// Free() on a KeySet after using it to prevent memory leaks.
+type KeySet[K any, C KeyCodec[K]] struct {
+	array Judy1
+	codec C
+}
+
+// Set key in the set.
+// Return true if key was previously absent, otherwise false.
+func (s *KeySet[K, C]) Set(key K) bool {
+	return s.array.Set(s.codec.EncodeKey(key))
+}
+
+// Unset key in the set.
+// Return true if key was previously present, otherwise false.
+func (s *KeySet[K, C]) Unset(key K) bool {
+	idx, side := queryKey(s.codec, key)
+	return side == 0 && s.array.Unset(idx)
+}
+
+// Test if key is present in the set.
+func (s *KeySet[K, C]) Test(key K) bool {
+	idx, side := queryKey(s.codec, key)
+	return side == 0 && s.array.Test(idx)
+}
+
+// Count the number of keys present in the set.
+func (s *KeySet[K, C]) CountAll() uint64 {
+	return s.array.CountAll()
+}
+
+// Count the number of keys present in the set between keyA and keyB (inclusive).
+func (s *KeySet[K, C]) CountFrom(keyA, keyB K) uint64 {
+	idxA, sideA := queryKey(s.codec, keyA)
+	idxB, sideB := queryKey(s.codec, keyB)
+	if sideA > 0 || sideB < 0 {
+		return 0
+	}
+	return s.array.CountFrom(idxA, idxB)
+}
+
+// Search (inclusive) for the first key present that is equal to or greater than the passed key.
+func (s *KeySet[K, C]) First(key K) (K, bool) {
+	idx, side := queryKey(s.codec, key)
+	if side > 0 {
+		return s.decode(0, false)
+	}
+	return s.decode(s.array.First(idx))
+}
+
+// Search (exclusive) for the first key present that is greater than the passed key.
+func (s *KeySet[K, C]) Next(key K) (K, bool) {
+	idx, side := queryKey(s.codec, key)
+	switch {
+	case side > 0:
+		return s.decode(0, false)
+	case side < 0:
+		return s.decode(s.array.First(0))
+	}
+	return s.decode(s.array.Next(idx))
+}
+
+// Search (inclusive) for the last key present that is equal to or less than the passed key.
+func (s *KeySet[K, C]) Last(key K) (K, bool) {
+	idx, side := queryKey(s.codec, key)
+	if side < 0 {
+		return s.decode(0, false)
+	}
+	return s.decode(s.array.Last(idx))
+}
+
+// Search (exclusive) for the last key present that is less than the passed key.
+func (s *KeySet[K, C]) Prev(key K) (K, bool) {
+	idx, side := queryKey(s.codec, key)
+	switch {
+	case side < 0:
+		return s.decode(0, false)
+	case side > 0:
+		return s.decode(s.array.Last(math.MaxUint64))
+	}
+	return s.decode(s.array.Prev(idx))
+}
+
+// Locate the Nth key that is present in the set (Nth = 1 returns the smallest key).
+func (s *KeySet[K, C]) ByCount(nth uint64) (K, bool) {
+	return s.decode(s.array.ByCount(nth))
+}
+
+// Range returns an iterator over the keys present between lo and hi (inclusive), in ascending order.
+func (s *KeySet[K, C]) Range(lo, hi K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		start, sideLo := queryKey(s.codec, lo)
+		end, sideHi := queryKey(s.codec, hi)
+		if sideLo > 0 || sideHi < 0 {
+			return
+		}
+		for idx, ok := s.array.First(start); ok && idx <= end; idx, ok = s.array.Next(idx) {
+			if !yield(s.codec.DecodeKey(idx)) {
+				return
+			}
+		}
+	}
+}
+
+// Array returns the underlying Judy1 array holding the encoded keys. It is freed with the set.
+func (s *KeySet[K, C]) Array() *Judy1 {
+	return &s.array
+}
+
+// Return the number of bytes of memory currently in use by the set.
+func (s *KeySet[K, C]) MemoryUsed() uint64 {
+	return s.array.MemoryUsed()
+}
+
+// Free the entire set.
+// Return the number of bytes freed.
+func (s *KeySet[K, C]) Free() uint64 {
+	return s.array.Free()
+}
+
+func (s *KeySet[K, C]) decode(index uint64, ok bool) (K, bool) {
+	if !ok {
+		var zero K
+		return zero, false
+	}
+	return s.codec.DecodeKey(index), true
+}
+
+// A KeyMap is a JudyL array of keys of type K to uint64 values, with the keys stored as indexes through the
+// order-preserving KeyCodec C. Iteration and range queries follow the order of K.
+// The default value of this struct is a valid empty map.
+//
+//    m := TimeMap{}
+//    defer m.Free()
+//
+//    m.Insert(time.Now(), 42)
+//    m.CountFrom(start, end) // number of entries between start and end
+//
+// NOTE: The underlying JudyL array allocates memory outside of the Go runtime. It is very important that you call
+// Free() on a KeyMap after using it to prevent memory leaks.
+type KeyMap[K any, C KeyCodec[K]] struct {
+	array JudyL
+	codec C
+}
+
+// Insert a Key and Value into the map. If the Key was already present, the current Value is replaced.
+func (m *KeyMap[K, C]) Insert(key K, value uint64) {
+	m.array.Insert(m.codec.EncodeKey(key), value)
+}
+
+// Delete the Key/Value pair from the map.
+// Returns true if successful. Returns false if Key was not present.
+func (m *KeyMap[K, C]) Delete(key K) bool {
+	idx, side := queryKey(m.codec, key)
+	return side == 0 && m.array.Delete(idx)
+}
+
+// Get the Value associated with Key in the map
+//   returns (value, true) if the key was found
+//   returns (_, false) if the key was not found
+func (m *KeyMap[K, C]) Get(key K) (uint64, bool) {
+	idx, side := queryKey(m.codec, key)
+	if side != 0 {
+		return 0, false
+	}
+	return m.array.Get(idx)
+}
+
+// Count the number of keys present in the map.
+func (m *KeyMap[K, C]) CountAll() uint64 {
+	return m.array.CountAll()
+}
+
+// Count the number of keys present in the map between keyA and keyB (inclusive).
+func (m *KeyMap[K, C]) CountFrom(keyA, keyB K) uint64 {
+	idxA, sideA := queryKey(m.codec, keyA)
+	idxB, sideB := queryKey(m.codec, keyB)
+	if sideA > 0 || sideB < 0 {
+		return 0
+	}
+	return m.array.CountFrom(idxA, idxB)
+}
+
+// Search (inclusive) for the first key present that is equal to or greater than the passed key.
+func (m *KeyMap[K, C]) First(key K) (K, uint64, bool) {
+	idx, side := queryKey(m.codec, key)
+	if side > 0 {
+		return m.decode(0, 0, false)
+	}
+	return m.decode(m.array.First(idx))
+}
+
+// Search (exclusive) for the first key present that is greater than the passed key.
+func (m *KeyMap[K, C]) Next(key K) (K, uint64, bool) {
+	idx, side := queryKey(m.codec, key)
+	switch {
+	case side > 0:
+		return m.decode(0, 0, false)
+	case side < 0:
+		return m.decode(m.array.First(0))
+	}
+	return m.decode(m.array.Next(idx))
+}
+
+// Search (inclusive) for the last key present that is equal to or less than the passed key.
+func (m *KeyMap[K, C]) Last(key K) (K, uint64, bool) {
+	idx, side := queryKey(m.codec, key)
+	if side < 0 {
+		return m.decode(0, 0, false)
+	}
+	return m.decode(m.array.Last(idx))
+}
+
+// Search (exclusive) for the last key present that is less than the passed key.
+func (m *KeyMap[K, C]) Prev(key K) (K, uint64, bool) {
+	idx, side := queryKey(m.codec, key)
+	switch {
+	case side < 0:
+		return m.decode(0, 0, false)
+	case side > 0:
+		return m.decode(m.array.Last(math.MaxUint64))
+	}
+	return m.decode(m.array.Prev(idx))
+}
+
+// Locate the Nth key that is present in the map (Nth = 1 returns the smallest key).
+func (m *KeyMap[K, C]) ByCount(nth uint64) (K, uint64, bool) {
+	return m.decode(m.array.ByCount(nth))
+}
+
+// Range returns an iterator over the key/value pairs whose key is between lo and hi (inclusive), in ascending key
+// order.
+func (m *KeyMap[K, C]) Range(lo, hi K) iter.Seq2[K, uint64] {
+	return func(yield func(K, uint64) bool) {
+		start, sideLo := queryKey(m.codec, lo)
+		end, sideHi := queryKey(m.codec, hi)
+		if sideLo > 0 || sideHi < 0 {
+			return
+		}
+		for idx, val, ok := m.array.First(start); ok && idx <= end; idx, val, ok = m.array.Next(idx) {
+			if !yield(m.codec.DecodeKey(idx), val) {
+				return
+			}
+		}
+	}
+}
+
+// Array returns the underlying JudyL array keyed by the encoded keys. It is freed with the map.
+func (m *KeyMap[K, C]) Array() *JudyL {
+	return &m.array
+}
+
+// Return the number of bytes of memory currently in use by the map.
+func (m *KeyMap[K, C]) MemoryUsed() uint64 {
+	return m.array.MemoryUsed()
+}
+
+// Free the entire map.
+// Return the number of bytes freed.
+func (m *KeyMap[K, C]) Free() uint64 {
+	return m.array.Free()
+}
+
+func (m *KeyMap[K, C]) decode(index, value uint64, ok bool) (K, uint64, bool) {
+	if !ok {
+		var zero K
+		return zero, 0, false
+	}
+	return m.codec.DecodeKey(index), value, true
+}
@@ -0,0 +1,265 @@
+package judy
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestInt64Key(t *testing.T) {
+
+	c := Int64Key{}
+	keys := []int64{math.MinInt64, -1000, -1, 0, 1, 1000, math.MaxInt64}
+	for n, k := range keys {
+		if r := c.DecodeKey(c.EncodeKey(k)); r != k {
+			t.Errorf("%v should round-trip, was %v", k, r)
+		}
+		if n > 0 && c.EncodeKey(keys[n-1]) >= c.EncodeKey(k) {
+			t.Errorf("%v should encode below %v", keys[n-1], k)
+		}
+	}
+	if c.EncodeKey(math.MinInt64) != 0 || c.EncodeKey(math.MaxInt64) != math.MaxUint64 {
+		t.Error("The int64 range should map onto the whole uint64 range")
+	}
+}
+
+func TestFloat64Key(t *testing.T) {
+
+	c := Float64Key{}
+	negNaN := math.Float64frombits(math.Float64bits(math.NaN()) | 1<<63)
+	keys := []float64{negNaN, math.Inf(-1), -math.MaxFloat64, -1.5, -math.SmallestNonzeroFloat64, math.Copysign(0, -1), 0,
+		math.SmallestNonzeroFloat64, 1, 1.5, math.MaxFloat64, math.Inf(1), math.NaN()}
+	for n, k := range keys {
+		if r := c.DecodeKey(c.EncodeKey(k)); math.Float64bits(r) != math.Float64bits(k) {
+			t.Errorf("%v should round-trip bit for bit, was %v", k, r)
+		}
+		if n > 0 && c.EncodeKey(keys[n-1]) >= c.EncodeKey(k) {
+			t.Errorf("%v should encode below %v", keys[n-1], k)
+		}
+	}
+}
+
+func TestTimeKey(t *testing.T) {
+
+	c := TimeKey{}
+	keys := []time.Time{
+		time.Unix(0, math.MinInt64),
+		time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Unix(0, -1),
+		time.Unix(0, 0),
+		time.Date(2013, 11, 26, 0, 0, 0, 0, time.UTC),
+		time.Unix(0, math.MaxInt64),
+	}
+	for n, k := range keys {
+		if r := c.DecodeKey(c.EncodeKey(k)); !r.Equal(k) {
+			t.Errorf("%v should round-trip, was %v", k, r)
+		}
+		if n > 0 && c.EncodeKey(keys[n-1]) >= c.EncodeKey(k) {
+			t.Errorf("%v should encode below %v", keys[n-1], k)
+		}
+	}
+
+	d := DurationKey{}
+	if d.EncodeKey(-time.Second) >= d.EncodeKey(0) || d.DecodeKey(d.EncodeKey(-time.Second)) != -time.Second {
+		t.Error("Negative durations should sort first and round-trip")
+	}
+}
+
+func TestInt64Set(t *testing.T) {
+
+	s := Int64Set{}
+	defer s.Free()
+
+	for _, k := range []int64{5, -3, 0, math.MinInt64, -100, math.MaxInt64, 42} {
+		s.Set(k)
+	}
+
+	var keys []int64
+	for k, ok := s.First(math.MinInt64); ok; k, ok = s.Next(k) {
+		keys = append(keys, k)
+	}
+	expected := []int64{math.MinInt64, -100, -3, 0, 5, 42, math.MaxInt64}
+	if !slices.Equal(keys, expected) {
+		t.Errorf("Keys should iterate as %v, was %v", expected, keys)
+	}
+
+	if ct := s.CountFrom(-100, 5); ct != 4 {
+		t.Errorf("CountFrom(-100, 5) should be 4, was %v", ct)
+	}
+	if got := slices.Collect(s.Range(-50, 50)); !slices.Equal(got, []int64{-3, 0, 5, 42}) {
+		t.Errorf("Range(-50, 50) should be [-3 0 5 42], was %v", got)
+	}
+	if got := slices.Collect(s.Range(50, -50)); len(got) != 0 {
+		t.Errorf("Range(50, -50) should be empty, was %v", got)
+	}
+	if k, ok := s.Last(-1); !ok || k != -3 {
+		t.Errorf("Last(-1) should be -3, was %v,%v", k, ok)
+	}
+	if k, ok := s.Prev(-3); !ok || k != -100 {
+		t.Errorf("Prev(-3) should be -100, was %v,%v", k, ok)
+	}
+	if k, ok := s.ByCount(2); !ok || k != -100 {
+		t.Errorf("ByCount(2) should be -100, was %v,%v", k, ok)
+	}
+	if _, ok := s.Next(math.MaxInt64); ok {
+		t.Error("Next(MaxInt64) should not be found")
+	}
+	if !s.Test(-3) || s.Test(-4) {
+		t.Error("Test should find -3 and not -4")
+	}
+	if !s.Unset(-3) || s.Unset(-3) {
+		t.Error("Unset(-3) should succeed once")
+	}
+	if ct := s.CountAll(); ct != 6 {
+		t.Errorf("Count should be 6, was %v", ct)
+	}
+}
+
+func TestFloat64Set(t *testing.T) {
+
+	s := Float64Set{}
+	defer s.Free()
+
+	for _, k := range []float64{2.5, -0.5, math.Inf(-1), 0, -7, 1e-300} {
+		s.Set(k)
+	}
+
+	if got := slices.Collect(s.Range(math.Inf(-1), math.Inf(1))); !slices.Equal(got, []float64{math.Inf(-1), -7, -0.5, 0, 1e-300, 2.5}) {
+		t.Errorf("Floats should iterate in numeric order, was %v", got)
+	}
+	if ct := s.CountFrom(-1, 1); ct != 3 {
+		t.Errorf("CountFrom(-1, 1) should be 3, was %v", ct)
+	}
+}
+
+func TestTimeMap(t *testing.T) {
+
+	m := TimeMap{}
+	defer m.Free()
+
+	base := time.Date(2013, 11, 26, 0, 0, 0, 0, time.UTC)
+	for i := -5; i <= 5; i++ {
+		m.Insert(base.Add(time.Duration(i)*time.Hour), uint64(i+5))
+	}
+	m.Insert(time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), 99)
+
+	if k, v, ok := m.First(time.Unix(0, math.MinInt64)); !ok || k.Year() != 1960 || v != 99 {
+		t.Errorf("The pre-epoch time should come first, was %v,%v,%v", k, v, ok)
+	}
+	if ct := m.CountFrom(base.Add(-2*time.Hour), base.Add(2*time.Hour)); ct != 5 {
+		t.Errorf("CountFrom should be 5, was %v", ct)
+	}
+
+	var vals []uint64
+	for k, v := range m.Range(base, base.Add(3*time.Hour)) {
+		if k.Before(base) {
+			t.Errorf("Range returned %v, before %v", k, base)
+		}
+		vals = append(vals, v)
+	}
+	if !slices.Equal(vals, []uint64{5, 6, 7, 8}) {
+		t.Errorf("Range values should be [5 6 7 8], was %v", vals)
+	}
+
+	if v, ok := m.Get(base); !ok || v != 5 {
+		t.Errorf("Get(base) should be 5, was %v,%v", v, ok)
+	}
+	if k, _, ok := m.Next(base); !ok || !k.Equal(base.Add(time.Hour)) {
+		t.Errorf("Next(base) should be an hour later, was %v,%v", k, ok)
+	}
+	if k, _, ok := m.Prev(base); !ok || !k.Equal(base.Add(-time.Hour)) {
+		t.Errorf("Prev(base) should be an hour earlier, was %v,%v", k, ok)
+	}
+	if k, _, ok := m.Last(time.Unix(0, math.MaxInt64)); !ok || !k.Equal(base.Add(5*time.Hour)) {
+		t.Errorf("Last should be the latest time, was %v,%v", k, ok)
+	}
+	if !m.Delete(base) || m.Delete(base) {
+		t.Error("Delete(base) should succeed once")
+	}
+	if ct := m.CountAll(); ct != 11 {
+		t.Errorf("Count should be 11, was %v", ct)
+	}
+}
+
+func TestTimeKeyQueryBounds(t *testing.T) {
+
+	s := TimeSet{}
+	defer s.Free()
+
+	earliest, latest := time.Unix(0, math.MinInt64), time.Unix(0, math.MaxInt64)
+	base := time.Date(2013, 11, 26, 0, 0, 0, 0, time.UTC)
+	s.Set(earliest)
+	s.Set(base)
+	s.Set(latest)
+
+	before, after := time.Time{}, time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if k, ok := s.First(before); !ok || !k.Equal(earliest) {
+		t.Errorf("First(zero time) should be the earliest time, was %v,%v", k, ok)
+	}
+	if k, ok := s.Next(before); !ok || !k.Equal(earliest) {
+		t.Errorf("Next(zero time) should be the earliest time, was %v,%v", k, ok)
+	}
+	if _, ok := s.First(after); ok {
+		t.Error("First(year 3000) should not be found")
+	}
+	if k, ok := s.Prev(after); !ok || !k.Equal(latest) {
+		t.Errorf("Prev(year 3000) should be the latest time, was %v,%v", k, ok)
+	}
+	if _, ok := s.Last(before); ok {
+		t.Error("Last(zero time) should not be found")
+	}
+	if ct := s.CountFrom(before, after); ct != 3 {
+		t.Errorf("CountFrom(zero time, year 3000) should be 3, was %v", ct)
+	}
+	if ct := s.CountFrom(after, after); ct != 0 {
+		t.Errorf("CountFrom(year 3000, year 3000) should be 0, was %v", ct)
+	}
+	if s.Test(before) || s.Unset(after) {
+		t.Error("Times outside the range should not be present")
+	}
+
+	m := TimeMap{}
+	defer m.Free()
+	m.Insert(base, 1)
+
+	var n int
+	for range m.Range(before, base) {
+		n++
+	}
+	if n != 1 {
+		t.Errorf("Range(zero time, base) should return 1 pair, returned %v", n)
+	}
+	if _, ok := m.Get(before); ok || m.Delete(after) {
+		t.Error("Times outside the range should not be present")
+	}
+	if k, _, ok := m.Prev(after); !ok || !k.Equal(base) {
+		t.Errorf("Prev(year 3000) should be base, was %v,%v", k, ok)
+	}
+
+	// a stored key must still be representable
+	defer func() {
+		if recover() == nil {
+			t.Error("Set of a time outside the range should panic")
+		}
+	}()
+	s.Set(before)
+}
+
+func TestInt64Map(t *testing.T) {
+
+	m := Int64Map{}
+	defer m.Free()
+
+	for i := int64(-10); i < 10; i++ {
+		m.Insert(i, uint64(i+10))
+	}
+
+	if k, v, ok := m.ByCount(1); !ok || k != -10 || v != 0 {
+		t.Errorf("ByCount(1) should be -10,0 was %v,%v,%v", k, v, ok)
+	}
+	if ct := m.Array().CountAll(); ct != 20 {
+		t.Errorf("Count should be 20, was %v", ct)
+	}
+}
@@ -0,0 +1,161 @@
+package judy
+
+import (
+	"iter"
+	"math"
+)
+
+// A SparseMatrix is a sparse matrix of float64 values indexed by uint64 row and column, such as a user × item
+// matrix, stored as a JudyL of rows whose values are JudyL arrays from column to the bits of the value (see
+// math.Float64bits). Only non-zero entries are stored and rows without entries take no memory, so a matrix costs a
+// few bytes per entry where a map[[2]uint64]float64 costs several times that.
+// The default value of this struct is a valid empty matrix.
+//
+//    m := SparseMatrix{}
+//    defer m.Free()
+//
+//    m.Set(user, item, 4.5)
+//    for item, rating := range m.Row(user) {
+//        ...
+//    }
+//
+// NOTE: The Judy arrays allocate memory outside of the Go runtime. It is very important that you call Free() on a
+// SparseMatrix after using it to prevent memory leaks.
+type SparseMatrix struct {
+	rows JudyLOfJudyL
+}
+
+// Set the entry at row and col to value. Setting an entry to 0 removes it, and a row whose last entry is removed is
+// removed as well.
+func (m *SparseMatrix) Set(row, col uint64, value float64) {
+	if value != 0 {
+		m.rows.GetOrCreate(row).Insert(col, math.Float64bits(value))
+		return
+	}
+	r := m.rows.Get(row)
+	if r != nil && r.Delete(col) && r.array == nil {
+		m.rows.Delete(row)
+	}
+}
+
+// Get the entry at row and col
+//   returns (value, true) if the entry is non-zero
+//   returns (0, false) if the entry is zero
+func (m *SparseMatrix) Get(row, col uint64) (float64, bool) {
+	if r := m.rows.Get(row); r != nil {
+		if bits, ok := r.Get(col); ok {
+			return math.Float64frombits(bits), true
+		}
+	}
+	return 0, false
+}
+
+// Count the number of rows with at least one non-zero entry.
+func (m *SparseMatrix) CountRows() uint64 {
+	return m.rows.CountAll()
+}
+
+// Count the number of non-zero entries in the matrix.
+func (m *SparseMatrix) CountNonZero() uint64 {
+	var n uint64
+	for _, r := range m.rows.All() {
+		n += r.CountAll()
+	}
+	return n
+}
+
+// Row returns an iterator over the columns and values of the non-zero entries of row, in ascending column order.
+func (m *SparseMatrix) Row(row uint64) iter.Seq2[uint64, float64] {
+	return func(yield func(uint64, float64) bool) {
+		r := m.rows.Get(row)
+		if r == nil {
+			return
+		}
+		for col, bits, ok := r.First(0); ok; col, bits, ok = r.Next(col) {
+			if !yield(col, math.Float64frombits(bits)) {
+				return
+			}
+		}
+	}
+}
+
+// Column returns an iterator over the rows and values of the non-zero entries of col, in ascending row order.
+// Entries are stored by row, so this looks col up in every row.
+func (m *SparseMatrix) Column(col uint64) iter.Seq2[uint64, float64] {
+	return func(yield func(uint64, float64) bool) {
+		for row, r, ok := m.rows.First(0); ok; row, r, ok = m.rows.Next(row) {
+			if bits, ok := r.Get(col); ok && !yield(row, math.Float64frombits(bits)) {
+				return
+			}
+		}
+	}
+}
+
+// Transpose returns a new matrix with the rows and columns of m swapped. Looking up columns of m is done more
+// cheaply with the rows of its transpose than with Column.
+// The caller must Free the returned matrix.
+func (m *SparseMatrix) Transpose() *SparseMatrix {
+	t := &SparseMatrix{}
+	for row, r := range m.rows.All() {
+		for col, bits, ok := r.First(0); ok; col, bits, ok = r.Next(col) {
+			t.rows.GetOrCreate(col).Insert(row, bits)
+		}
+	}
+	return t
+}
+
+// MulVec returns the sparse matrix-vector product m·x, where x maps column to value. Only the rows of the result
+// that are non-zero are present.
+// The caller must Free the returned vector.
+func (m *SparseMatrix) MulVec(x *TypedL[float64, Float64Codec]) *TypedL[float64, Float64Codec] {
+	y := &TypedL[float64, Float64Codec]{}
+	for row, r := range m.rows.All() {
+		var sum float64
+		for col, bits, ok := r.First(0); ok; col, bits, ok = r.Next(col) {
+			if v, ok := x.Get(col); ok {
+				sum += math.Float64frombits(bits) * v
+			}
+		}
+		if sum != 0 {
+			y.Insert(row, sum)
+		}
+	}
+	return y
+}
+
+// A CSR is a SparseMatrix exported in compressed sparse row form. Rows lists the non-empty rows in ascending order,
+// and the entries of Rows[i] are Cols[RowPtr[i]:RowPtr[i+1]] and Values[RowPtr[i]:RowPtr[i+1]], in ascending column
+// order.
+type CSR struct {
+	Rows   []uint64
+	RowPtr []int
+	Cols   []uint64
+	Values []float64
+}
+
+// CSR exports the matrix in compressed sparse row form. Each row is copied out of its JudyL with a single Page call.
+func (m *SparseMatrix) CSR() CSR {
+	c := CSR{RowPtr: []int{0}}
+	var bits []uint64
+	for row, r := range m.rows.All() {
+		n := int(r.CountAll())
+		c.Cols, bits = r.Page(0, n, c.Cols, bits[:0])
+		for _, b := range bits {
+			c.Values = append(c.Values, math.Float64frombits(b))
+		}
+		c.Rows = append(c.Rows, row)
+		c.RowPtr = append(c.RowPtr, len(c.Cols))
+	}
+	return c
+}
+
+// Return the number of bytes of memory currently in use by the matrix.
+func (m *SparseMatrix) MemoryUsed() uint64 {
+	return m.rows.MemoryUsed()
+}
+
+// Free the entire matrix.
+// Return the number of bytes freed.
+func (m *SparseMatrix) Free() uint64 {
+	return m.rows.Free()
+}
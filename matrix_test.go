@@ -0,0 +1,187 @@
+package judy
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestEmptySparseMatrix(t *testing.T) {
+
+	m := SparseMatrix{}
+	if r := m.Free(); r != 0 {
+		t.Errorf("Free should return 0, returned %v", r)
+	}
+	if _, ok := m.Get(1, 2); ok {
+		t.Error("Get on an empty matrix should not be found")
+	}
+	for range m.Row(1) {
+		t.Error("Row on an empty matrix should be empty")
+	}
+	m.Set(1, 2, 0)
+	if ct := m.CountRows(); ct != 0 {
+		t.Errorf("Setting 0 should not create a row, count was %v", ct)
+	}
+}
+
+func TestSparseMatrixSetGet(t *testing.T) {
+
+	m := SparseMatrix{}
+	defer m.Free()
+
+	m.Set(1, 10, 1.5)
+	m.Set(1, 20, -2)
+	m.Set(math.MaxUint64, 5, math.Inf(1))
+
+	if v, ok := m.Get(1, 20); !ok || v != -2 {
+		t.Errorf("Get(1, 20) should be -2, was %v,%v", v, ok)
+	}
+	if v, ok := m.Get(math.MaxUint64, 5); !ok || !math.IsInf(v, 1) {
+		t.Errorf("Get(MaxUint64, 5) should be +Inf, was %v,%v", v, ok)
+	}
+	if ct := m.CountNonZero(); ct != 3 {
+		t.Errorf("CountNonZero should be 3, was %v", ct)
+	}
+
+	m.Set(1, 10, 0)
+	m.Set(1, 20, 0)
+	if _, ok := m.Get(1, 10); ok {
+		t.Error("Setting an entry to 0 should remove it")
+	}
+	if ct := m.CountRows(); ct != 1 {
+		t.Errorf("A row whose entries are removed should be removed, count was %v", ct)
+	}
+}
+
+func newTestMatrix(rng *rand.Rand, rows, cols uint64, n int) (*SparseMatrix, map[[2]uint64]float64) {
+	m := &SparseMatrix{}
+	dense := map[[2]uint64]float64{}
+	for i := 0; i < n; i++ {
+		row, col := uint64(rng.Int63n(int64(rows))), uint64(rng.Int63n(int64(cols)))
+		v := float64(rng.Intn(9) + 1)
+		m.Set(row, col, v)
+		dense[[2]uint64{row, col}] = v
+	}
+	return m, dense
+}
+
+func TestSparseMatrixRowsColumns(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(1))
+	m, dense := newTestMatrix(rng, 100, 100, 800)
+	defer m.Free()
+
+	if ct := m.CountNonZero(); ct != uint64(len(dense)) {
+		t.Errorf("CountNonZero should be %v, was %v", len(dense), ct)
+	}
+
+	for i := uint64(0); i < 100; i++ {
+		var prev uint64
+		n := 0
+		for col, v := range m.Row(i) {
+			if n > 0 && col <= prev {
+				t.Fatalf("Row(%v) should be in ascending column order", i)
+			}
+			if dense[[2]uint64{i, col}] != v {
+				t.Errorf("Row(%v) entry %v should be %v, was %v", i, col, dense[[2]uint64{i, col}], v)
+			}
+			prev = col
+			n++
+		}
+		for row, v := range m.Column(i) {
+			if dense[[2]uint64{row, i}] != v {
+				t.Errorf("Column(%v) entry %v should be %v, was %v", i, row, dense[[2]uint64{row, i}], v)
+			}
+			n--
+		}
+		for key := range dense {
+			if key[0] == i {
+				n--
+			}
+			if key[1] == i {
+				n++
+			}
+		}
+		if n != 0 {
+			t.Errorf("Row and Column %v returned the wrong number of entries", i)
+		}
+	}
+
+	tr := m.Transpose()
+	defer tr.Free()
+	if ct := tr.CountNonZero(); ct != uint64(len(dense)) {
+		t.Errorf("Transpose CountNonZero should be %v, was %v", len(dense), ct)
+	}
+	for key, v := range dense {
+		if got, ok := tr.Get(key[1], key[0]); !ok || got != v {
+			t.Errorf("Transpose entry %v,%v should be %v, was %v,%v", key[1], key[0], v, got, ok)
+		}
+	}
+}
+
+func TestSparseMatrixMulVec(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(2))
+	m, dense := newTestMatrix(rng, 50, 200, 500)
+	defer m.Free()
+
+	x := &TypedL[float64, Float64Codec]{}
+	defer x.Free()
+	xs := map[uint64]float64{}
+	for col := uint64(0); col < 200; col += 3 {
+		x.Insert(col, float64(col%7)-3)
+		xs[col] = float64(col%7) - 3
+	}
+
+	expected := map[uint64]float64{}
+	for key, v := range dense {
+		expected[key[0]] += v * xs[key[1]]
+	}
+
+	y := m.MulVec(x)
+	defer y.Free()
+	for row, v := range expected {
+		if got, _ := y.Get(row); got != v {
+			t.Errorf("Row %v of the product should be %v, was %v", row, v, got)
+		}
+		if _, ok := y.Get(row); ok != (v != 0) {
+			t.Errorf("Row %v of the product should only be present if non-zero", row)
+		}
+	}
+}
+
+func TestSparseMatrixCSR(t *testing.T) {
+
+	m := SparseMatrix{}
+	defer m.Free()
+
+	m.Set(2, 7, 1)
+	m.Set(2, 3, 2)
+	m.Set(5, 0, 3)
+	m.Set(9, 9, 4)
+	m.Set(9, 1, 5)
+	m.Set(9, 4, 6)
+
+	c := m.CSR()
+	if !slices.Equal(c.Rows, []uint64{2, 5, 9}) {
+		t.Errorf("Rows should be [2 5 9], was %v", c.Rows)
+	}
+	if !slices.Equal(c.RowPtr, []int{0, 2, 3, 6}) {
+		t.Errorf("RowPtr should be [0 2 3 6], was %v", c.RowPtr)
+	}
+	if !slices.Equal(c.Cols, []uint64{3, 7, 0, 1, 4, 9}) {
+		t.Errorf("Cols should be [3 7 0 1 4 9], was %v", c.Cols)
+	}
+	if !slices.Equal(c.Values, []float64{2, 1, 3, 5, 6, 4}) {
+		t.Errorf("Values should be [2 1 3 5 6 4], was %v", c.Values)
+	}
+
+	empty := SparseMatrix{}
+	if c := empty.CSR(); len(c.Rows) != 0 || !slices.Equal(c.RowPtr, []int{0}) {
+		t.Errorf("CSR of an empty matrix should have RowPtr [0], was %v", c.RowPtr)
+	}
+	if m.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
@@ -0,0 +1,181 @@
+package judy
+
+/*
+#cgo LDFLAGS: -lJudy
+#include <Judy.h>
+
+enum { MERGE_SUM = 1, MERGE_MAX, MERGE_MIN, MERGE_KEEP_FIRST, MERGE_KEEP_LAST };
+
+// Merge every index/value pair of src into *dst, resolving indexes present in both with op.
+static void judyLMerge(PPvoid_t dst, Pcvoid_t src, int op) {
+	Word_t idx = 0;
+	PPvoid_t psrc;
+	for (psrc = JudyLFirst(src, &idx, NULL); psrc != NULL; psrc = JudyLNext(src, &idx, NULL)) {
+		Word_t b = *(Word_t *)psrc;
+		Word_t *pdst = (Word_t *)JudyLGet(*dst, idx, NULL);
+		if (pdst == NULL) {
+			*(Word_t *)JudyLIns(dst, idx, NULL) = b;
+			continue;
+		}
+		switch (op) {
+		case MERGE_SUM:
+			*pdst += b;
+			break;
+		case MERGE_MAX:
+			if (b > *pdst) *pdst = b;
+			break;
+		case MERGE_MIN:
+			if (b < *pdst) *pdst = b;
+			break;
+		case MERGE_KEEP_LAST:
+			*pdst = b;
+			break;
+		}
+	}
+}
+*/
+import "C"
+
+import (
+	"container/heap"
+	"unsafe"
+)
+
+// A Resolver decides the merged value of an index that is present in both arrays of a merge, where a is the value
+// already in the destination array and b is the value from the source array.
+type Resolver func(index, a, b uint64) uint64
+
+// The built-in resolvers below are the common Sum, Max, Min, KeepFirst and KeepLast resolvers, named with a Merge
+// prefix because in this package a bare judy.Max or judy.Min would read as an operation on an array.
+
+// MergeSum resolves a merge conflict by adding the two values (wrapping on overflow).
+func MergeSum(index, a, b uint64) uint64 { return a + b }
+
+// MergeMax resolves a merge conflict by keeping the larger value.
+func MergeMax(index, a, b uint64) uint64 { return max(a, b) }
+
+// MergeMin resolves a merge conflict by keeping the smaller value.
+func MergeMin(index, a, b uint64) uint64 { return min(a, b) }
+
+// MergeKeepFirst resolves a merge conflict by keeping the value already in the destination array.
+func MergeKeepFirst(index, a, b uint64) uint64 { return a }
+
+// MergeKeepLast resolves a merge conflict by taking the value from the source array.
+func MergeKeepLast(index, a, b uint64) uint64 { return b }
+
+// MergeInto merges every index/value pair of the JudyL array src into dst. Indexes only present in src are copied,
+// and indexes present in both arrays get the value resolve(index, dstValue, srcValue). src is not modified.
+//
+// When resolve is one of MergeSum, MergeMax, MergeMin, MergeKeepFirst or MergeKeepLast the whole merge runs in a
+// single call into C, without calling back into Go for every index. Any other resolver (including a closure
+// wrapping one of these) is called from Go for each conflicting index.
+//
+//    totals := JudyL{}
+//    defer totals.Free()
+//    for _, partial := range partials {
+//        judy.MergeInto(&totals, partial, judy.MergeSum)
+//    }
+func MergeInto(dst, src *JudyL, resolve Resolver) {
+	if op := mergeOp(resolve); op != 0 {
+		C.judyLMerge(C.PPvoid_t(&dst.array), C.Pcvoid_t(src.array), op)
+		return
+	}
+
+	for idx, b, ok := src.First(0); ok; idx, b, ok = src.Next(idx) {
+		if a, found := dst.Get(idx); found {
+			dst.Insert(idx, resolve(idx, a, b))
+		} else {
+			dst.Insert(idx, b)
+		}
+	}
+}
+
+// MergeAll merges every index/value pair of the JudyL arrays srcs into dst. For an index present in more than one
+// array, the values are folded with resolve in order: the value in dst (if any) first, then the values of srcs in
+// argument order. None of srcs are modified.
+//
+// With one of the built-in resolvers each source is merged in C, as with MergeInto. Otherwise the sources are
+// walked together in a single k-way pass, so dst is updated once per distinct index.
+func MergeAll(dst *JudyL, resolve Resolver, srcs ...*JudyL) {
+	if op := mergeOp(resolve); op != 0 {
+		for _, src := range srcs {
+			C.judyLMerge(C.PPvoid_t(&dst.array), C.Pcvoid_t(src.array), op)
+		}
+		return
+	}
+
+	h := make(mergeHeap, 0, len(srcs))
+	for n, src := range srcs {
+		if idx, val, ok := src.First(0); ok {
+			h = append(h, mergeCursor{index: idx, value: val, src: n})
+		}
+	}
+	heap.Init(&h)
+
+	for len(h) > 0 {
+		idx := h[0].index
+		acc, found := dst.Get(idx)
+		for len(h) > 0 && h[0].index == idx {
+			c := &h[0]
+			if found {
+				acc = resolve(idx, acc, c.value)
+			} else {
+				acc, found = c.value, true
+			}
+			if next, val, ok := srcs[c.src].Next(idx); ok {
+				c.index, c.value = next, val
+				heap.Fix(&h, 0)
+			} else {
+				heap.Pop(&h)
+			}
+		}
+		dst.Insert(idx, acc)
+	}
+}
+
+// mergeOp returns the C fast path for resolve, or 0 if it has none.
+func mergeOp(resolve Resolver) C.int {
+	switch funcID(resolve) {
+	case funcID(MergeSum):
+		return C.MERGE_SUM
+	case funcID(MergeMax):
+		return C.MERGE_MAX
+	case funcID(MergeMin):
+		return C.MERGE_MIN
+	case funcID(MergeKeepFirst):
+		return C.MERGE_KEEP_FIRST
+	case funcID(MergeKeepLast):
+		return C.MERGE_KEEP_LAST
+	}
+	return 0
+}
+
+// funcID returns the identity of a func value, which is a pointer to the closure record of the function. A
+// top-level function has a single static record, shared by every func value made from it, while each closure has
+// its own, so a built-in resolver is recognized however it is passed and nothing else is mistaken for one.
+func funcID(f Resolver) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&f))
+}
+
+// mergeCursor is the current position of one source array in a k-way merge.
+type mergeCursor struct {
+	index uint64
+	value uint64
+	src   int
+}
+
+// mergeHeap orders cursors by index, then by source position so that values are folded in argument order.
+type mergeHeap []mergeCursor
+
+func (h mergeHeap) Len() int { return len(h) }
+func (h mergeHeap) Less(a, b int) bool {
+	return h[a].index < h[b].index || h[a].index == h[b].index && h[a].src < h[b].src
+}
+func (h mergeHeap) Swap(a, b int) { h[a], h[b] = h[b], h[a] }
+func (h *mergeHeap) Push(x any)   { *h = append(*h, x.(mergeCursor)) }
+func (h *mergeHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
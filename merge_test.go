@@ -0,0 +1,143 @@
+package judy
+
+import (
+	"math"
+	"testing"
+)
+
+func newMergeTestArrays() (JudyL, JudyL) {
+	dst, src := JudyL{}, JudyL{}
+	dst.Insert(1, 10)
+	dst.Insert(2, 20)
+	dst.Insert(math.MaxUint64, 5)
+	src.Insert(0, 7)
+	src.Insert(2, 3)
+	src.Insert(math.MaxUint64, 50)
+	return dst, src
+}
+
+func TestMergeInto(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		resolve Resolver
+		at2     uint64
+		atMax   uint64
+	}{
+		{"MergeSum", MergeSum, 23, 55},
+		{"MergeMax", MergeMax, 20, 50},
+		{"MergeMin", MergeMin, 3, 5},
+		{"MergeKeepFirst", MergeKeepFirst, 20, 5},
+		{"MergeKeepLast", MergeKeepLast, 3, 50},
+		{"closure", func(index, a, b uint64) uint64 { return a*100 + b }, 2003, 550},
+		{"wrapped", func(index, a, b uint64) uint64 { return MergeMin(index, a, b) + 1 }, 4, 6},
+	}
+
+	for _, tt := range tests {
+		dst, src := newMergeTestArrays()
+
+		MergeInto(&dst, &src, tt.resolve)
+
+		if ct := dst.CountAll(); ct != 4 {
+			t.Errorf("%v: count should be 4, was %v", tt.name, ct)
+		}
+		if v, ok := dst.Get(0); !ok || v != 7 {
+			t.Errorf("%v: index 0 should be copied from src, was %v,%v", tt.name, v, ok)
+		}
+		if v, ok := dst.Get(1); !ok || v != 10 {
+			t.Errorf("%v: index 1 should be kept from dst, was %v,%v", tt.name, v, ok)
+		}
+		if v, _ := dst.Get(2); v != tt.at2 {
+			t.Errorf("%v: index 2 should be %v, was %v", tt.name, tt.at2, v)
+		}
+		if v, _ := dst.Get(math.MaxUint64); v != tt.atMax {
+			t.Errorf("%v: index MaxUint64 should be %v, was %v", tt.name, tt.atMax, v)
+		}
+		if ct := src.CountAll(); ct != 3 {
+			t.Errorf("%v: src should not be modified, count was %v", tt.name, ct)
+		}
+
+		dst.Free()
+		src.Free()
+	}
+}
+
+func TestMergeIntoEmpty(t *testing.T) {
+
+	dst := JudyL{}
+	defer dst.Free()
+	src := JudyL{}
+	defer src.Free()
+
+	MergeInto(&dst, &src, MergeSum)
+	if ct := dst.CountAll(); ct != 0 {
+		t.Errorf("Merging empty arrays should be empty, count was %v", ct)
+	}
+
+	src.Insert(5, 6)
+	MergeInto(&dst, &src, MergeMin)
+	if v, ok := dst.Get(5); !ok || v != 6 {
+		t.Errorf("Merging into an empty array should copy src, was %v,%v", v, ok)
+	}
+}
+
+func TestMergeAll(t *testing.T) {
+
+	var srcs []*JudyL
+	for n := 0; n < 5; n++ {
+		src := &JudyL{}
+		defer src.Free()
+		var i uint64
+		for i = 0; i < 100; i++ {
+			if i%uint64(n+1) == 0 {
+				src.Insert(i, uint64(n+1))
+			}
+		}
+		srcs = append(srcs, src)
+	}
+
+	sums, folded := JudyL{}, JudyL{}
+	defer sums.Free()
+	defer folded.Free()
+	sums.Insert(0, 1000)
+	folded.Insert(0, 1000)
+
+	MergeAll(&sums, MergeSum, srcs...)
+	// a closure takes the k-way path; it records the fold order as decimal digits
+	MergeAll(&folded, func(index, a, b uint64) uint64 { return a*10 + b }, srcs...)
+
+	if ct := sums.CountAll(); ct != 100 {
+		t.Errorf("Count should be 100, was %v", ct)
+	}
+	if v, _ := sums.Get(0); v != 1015 {
+		t.Errorf("Sum at 0 should be 1015, was %v", v)
+	}
+	if v, _ := sums.Get(12); v != 1+2+3+4 {
+		t.Errorf("Sum at 12 should be 10, was %v", v)
+	}
+	if v, _ := folded.Get(0); v != 100012345 {
+		t.Errorf("Fold at 0 should be 100012345, was %v", v)
+	}
+	if v, _ := folded.Get(12); v != 1234 {
+		t.Errorf("Fold at 12 should be 1234, was %v", v)
+	}
+	if v, _ := folded.Get(97); v != 1 {
+		t.Errorf("Fold at 97 should be 1, was %v", v)
+	}
+	for c := range Diff(&sums, &folded) {
+		if c.Kind != Modified {
+			t.Errorf("Both merges should produce the same indexes, got %+v", c)
+		}
+	}
+}
+
+func TestMergeOp(t *testing.T) {
+
+	var f Resolver = MergeMax
+	if mergeOp(MergeSum) == 0 || mergeOp(f) != mergeOp(MergeMax) || mergeOp(MergeMax) == mergeOp(MergeMin) {
+		t.Error("Built-in resolvers should have their own C fast path")
+	}
+	if mergeOp(func(index, a, b uint64) uint64 { return a + b }) != 0 || mergeOp(nil) != 0 {
+		t.Error("Other resolvers should not have a C fast path")
+	}
+}
@@ -0,0 +1,214 @@
+package judy
+
+import "iter"
+
+// A JudyLOfJudy1 is a JudyL array whose values are Judy1 arrays, the standard Judy idiom for multi-level indexes.
+// Each child Judy1 array lives in place in the value word of its index, so no Go memory is involved.
+// The default value of this struct is a valid empty array.
+//
+//    j := JudyLOfJudy1{}
+//    defer j.Free() // frees every child array as well
+//
+//    j.GetOrCreate(termID).Set(docID)
+//    docs := j.Get(termID) // nil if termID has no child array
+//
+// The *Judy1 returned by Get, GetOrCreate and the traversal methods points into the parent array, so it is only
+// valid until the parent is next modified by creating or deleting a child (which may move the value words).
+// Setting and unsetting bits in a child does not modify the parent and is always allowed.
+//
+// NOTE: The Judy arrays allocate memory outside of the Go runtime. It is very important that you call Free() on a
+// JudyLOfJudy1 after using it to prevent memory leaks.
+type JudyLOfJudy1 struct {
+	array JudyL
+}
+
+// Get the child Judy1 array at index, or nil if index has none.
+func (j *JudyLOfJudy1) Get(index uint64) *Judy1 {
+	return (*Judy1)(j.array.slot(index))
+}
+
+// GetOrCreate returns the child Judy1 array at index, creating an empty one if index has none.
+func (j *JudyLOfJudy1) GetOrCreate(index uint64) *Judy1 {
+	return (*Judy1)(j.array.insertSlot(index))
+}
+
+// Delete frees the child Judy1 array at index and removes index.
+// Returns true if successful. Returns false if index was not present.
+func (j *JudyLOfJudy1) Delete(index uint64) bool {
+	child := j.Get(index)
+	if child == nil {
+		return false
+	}
+	child.Free()
+	return j.array.Delete(index)
+}
+
+// Count the number of child arrays.
+func (j *JudyLOfJudy1) CountAll() uint64 {
+	return j.array.CountAll()
+}
+
+// Count the number of child arrays between indexA and indexB (inclusive).
+func (j *JudyLOfJudy1) CountFrom(indexA, indexB uint64) uint64 {
+	return j.array.CountFrom(indexA, indexB)
+}
+
+// Search (inclusive) for the first index with a child array that is equal to or greater than the passed index.
+func (j *JudyLOfJudy1) First(index uint64) (uint64, *Judy1, bool) {
+	idx, p := j.array.firstSlot(index)
+	return idx, (*Judy1)(p), p != nil
+}
+
+// Search (exclusive) for the first index with a child array that is greater than the passed index.
+func (j *JudyLOfJudy1) Next(index uint64) (uint64, *Judy1, bool) {
+	idx, p := j.array.nextSlot(index)
+	return idx, (*Judy1)(p), p != nil
+}
+
+// Search (inclusive) for the last index with a child array that is equal to or less than the passed index.
+func (j *JudyLOfJudy1) Last(index uint64) (uint64, *Judy1, bool) {
+	idx, p := j.array.lastSlot(index)
+	return idx, (*Judy1)(p), p != nil
+}
+
+// Search (exclusive) for the last index with a child array that is less than the passed index.
+func (j *JudyLOfJudy1) Prev(index uint64) (uint64, *Judy1, bool) {
+	idx, p := j.array.prevSlot(index)
+	return idx, (*Judy1)(p), p != nil
+}
+
+// All returns an iterator over the indexes and child arrays, in ascending index order. Each step resumes with Next,
+// so children may be created and deleted while iterating, but a yielded child is only valid until then.
+func (j *JudyLOfJudy1) All() iter.Seq2[uint64, *Judy1] {
+	return func(yield func(uint64, *Judy1) bool) {
+		for idx, child, ok := j.First(0); ok; idx, child, ok = j.Next(idx) {
+			if !yield(idx, child) {
+				return
+			}
+		}
+	}
+}
+
+// Return the number of bytes of memory currently in use by the array and all of its children.
+func (j *JudyLOfJudy1) MemoryUsed() uint64 {
+	mem := j.array.MemoryUsed()
+	for idx, child, ok := j.First(0); ok; idx, child, ok = j.Next(idx) {
+		mem += child.MemoryUsed()
+	}
+	return mem
+}
+
+// Free every child array and then the entire array.
+// Return the number of bytes freed.
+func (j *JudyLOfJudy1) Free() uint64 {
+	var freed uint64
+	for idx, child, ok := j.First(0); ok; idx, child, ok = j.Next(idx) {
+		freed += child.Free()
+	}
+	return freed + j.array.Free()
+}
+
+// A JudyLOfJudyL is a JudyL array whose values are JudyL arrays, the standard Judy idiom for multi-level maps.
+// Each child JudyL array lives in place in the value word of its index, so no Go memory is involved.
+// The default value of this struct is a valid empty array.
+//
+//    j := JudyLOfJudyL{}
+//    defer j.Free() // frees every child array as well
+//
+//    j.GetOrCreate(row).Insert(column, value)
+//
+// The *JudyL returned by Get, GetOrCreate and the traversal methods points into the parent array, so it is only
+// valid until the parent is next modified by creating or deleting a child (which may move the value words).
+// Inserting into and deleting from a child does not modify the parent and is always allowed.
+//
+// NOTE: The Judy arrays allocate memory outside of the Go runtime. It is very important that you call Free() on a
+// JudyLOfJudyL after using it to prevent memory leaks.
+type JudyLOfJudyL struct {
+	array JudyL
+}
+
+// Get the child JudyL array at index, or nil if index has none.
+func (j *JudyLOfJudyL) Get(index uint64) *JudyL {
+	return (*JudyL)(j.array.slot(index))
+}
+
+// GetOrCreate returns the child JudyL array at index, creating an empty one if index has none.
+func (j *JudyLOfJudyL) GetOrCreate(index uint64) *JudyL {
+	return (*JudyL)(j.array.insertSlot(index))
+}
+
+// Delete frees the child JudyL array at index and removes index.
+// Returns true if successful. Returns false if index was not present.
+func (j *JudyLOfJudyL) Delete(index uint64) bool {
+	child := j.Get(index)
+	if child == nil {
+		return false
+	}
+	child.Free()
+	return j.array.Delete(index)
+}
+
+// Count the number of child arrays.
+func (j *JudyLOfJudyL) CountAll() uint64 {
+	return j.array.CountAll()
+}
+
+// Count the number of child arrays between indexA and indexB (inclusive).
+func (j *JudyLOfJudyL) CountFrom(indexA, indexB uint64) uint64 {
+	return j.array.CountFrom(indexA, indexB)
+}
+
+// Search (inclusive) for the first index with a child array that is equal to or greater than the passed index.
+func (j *JudyLOfJudyL) First(index uint64) (uint64, *JudyL, bool) {
+	idx, p := j.array.firstSlot(index)
+	return idx, (*JudyL)(p), p != nil
+}
+
+// Search (exclusive) for the first index with a child array that is greater than the passed index.
+func (j *JudyLOfJudyL) Next(index uint64) (uint64, *JudyL, bool) {
+	idx, p := j.array.nextSlot(index)
+	return idx, (*JudyL)(p), p != nil
+}
+
+// Search (inclusive) for the last index with a child array that is equal to or less than the passed index.
+func (j *JudyLOfJudyL) Last(index uint64) (uint64, *JudyL, bool) {
+	idx, p := j.array.lastSlot(index)
+	return idx, (*JudyL)(p), p != nil
+}
+
+// Search (exclusive) for the last index with a child array that is less than the passed index.
+func (j *JudyLOfJudyL) Prev(index uint64) (uint64, *JudyL, bool) {
+	idx, p := j.array.prevSlot(index)
+	return idx, (*JudyL)(p), p != nil
+}
+
+// All returns an iterator over the indexes and child arrays, in ascending index order. Each step resumes with Next,
+// so children may be created and deleted while iterating, but a yielded child is only valid until then.
+func (j *JudyLOfJudyL) All() iter.Seq2[uint64, *JudyL] {
+	return func(yield func(uint64, *JudyL) bool) {
+		for idx, child, ok := j.First(0); ok; idx, child, ok = j.Next(idx) {
+			if !yield(idx, child) {
+				return
+			}
+		}
+	}
+}
+
+// Return the number of bytes of memory currently in use by the array and all of its children.
+func (j *JudyLOfJudyL) MemoryUsed() uint64 {
+	mem := j.array.MemoryUsed()
+	for idx, child, ok := j.First(0); ok; idx, child, ok = j.Next(idx) {
+		mem += child.MemoryUsed()
+	}
+	return mem
+}
+
+// Free every child array and then the entire array.
+// Return the number of bytes freed.
+func (j *JudyLOfJudyL) Free() uint64 {
+	var freed uint64
+	for idx, child, ok := j.First(0); ok; idx, child, ok = j.Next(idx) {
+		freed += child.Free()
+	}
+	return freed + j.array.Free()
+}
@@ -0,0 +1,174 @@
+package judy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEmptyJudyLOfJudy1(t *testing.T) {
+
+	j := JudyLOfJudy1{}
+	if r := j.Free(); r != 0 {
+		t.Errorf("Free should return 0, returned %v", r)
+	}
+	if c := j.Get(5); c != nil {
+		t.Error("Get on an empty array should return nil")
+	}
+}
+
+func TestJudyLOfJudy1GetOrCreate(t *testing.T) {
+
+	j := JudyLOfJudy1{}
+	defer j.Free()
+
+	var i, k uint64
+	for i = 0; i < 50; i++ {
+		for k = 0; k <= i; k++ {
+			j.GetOrCreate(i * 1000).Set(k)
+		}
+	}
+
+	if ct := j.CountAll(); ct != 50 {
+		t.Errorf("Count should be 50, was %v", ct)
+	}
+	for i = 0; i < 50; i++ {
+		c := j.Get(i * 1000)
+		if c == nil {
+			t.Fatalf("Child %v should exist", i*1000)
+		}
+		if ct := c.CountAll(); ct != i+1 {
+			t.Errorf("Child %v count should be %v, was %v", i*1000, i+1, ct)
+		}
+	}
+	if c := j.Get(1); c != nil {
+		t.Error("Get(1) should return nil")
+	}
+	if ct := j.CountFrom(0, 9999); ct != 10 {
+		t.Errorf("CountFrom(0, 9999) should be 10, was %v", ct)
+	}
+
+	c := j.GetOrCreate(3000)
+	if !c.Test(3) || c.Test(4) {
+		t.Error("GetOrCreate should return the existing child")
+	}
+}
+
+func TestJudyLOfJudy1Traversal(t *testing.T) {
+
+	j := JudyLOfJudy1{}
+	defer j.Free()
+
+	j.GetOrCreate(10).Set(1)
+	j.GetOrCreate(20).Set(2)
+	j.GetOrCreate(math.MaxUint64).Set(3)
+
+	if idx, c, ok := j.First(11); !ok || idx != 20 || !c.Test(2) {
+		t.Errorf("First(11) should be 20, was %v,%v", idx, ok)
+	}
+	if idx, _, ok := j.Next(20); !ok || idx != math.MaxUint64 {
+		t.Errorf("Next(20) should be MaxUint64, was %v,%v", idx, ok)
+	}
+	if idx, _, ok := j.Last(19); !ok || idx != 10 {
+		t.Errorf("Last(19) should be 10, was %v,%v", idx, ok)
+	}
+	if _, _, ok := j.Prev(10); ok {
+		t.Error("Prev(10) should not be found")
+	}
+
+	var idxs []uint64
+	for idx, c := range j.All() {
+		idxs = append(idxs, idx)
+		c.Set(100)
+	}
+	if len(idxs) != 3 || idxs[0] != 10 || idxs[2] != math.MaxUint64 {
+		t.Errorf("All should return [10 20 MaxUint64], was %v", idxs)
+	}
+	if !j.Get(20).Test(100) {
+		t.Error("Children should be modifiable in place")
+	}
+}
+
+func TestJudyLOfJudy1DeleteFree(t *testing.T) {
+
+	j := JudyLOfJudy1{}
+
+	var i uint64
+	for i = 0; i < 1000; i++ {
+		j.GetOrCreate(i % 10).Set(i)
+	}
+
+	if !j.Delete(3) || j.Delete(3) {
+		t.Error("Delete(3) should succeed once")
+	}
+	if ct := j.CountAll(); ct != 9 {
+		t.Errorf("Count should be 9, was %v", ct)
+	}
+
+	// delete every other child while iterating
+	for idx := range j.All() {
+		if idx%2 == 0 {
+			j.Delete(idx)
+		}
+	}
+	if ct := j.CountAll(); ct != 4 {
+		t.Errorf("Count should be 4, was %v", ct)
+	}
+
+	mem := j.MemoryUsed()
+	if mem <= j.array.MemoryUsed() {
+		t.Error("MemoryUsed should include the children")
+	}
+	if r := j.Free(); r == 0 {
+		t.Error("Free should return the bytes freed")
+	}
+	if ct := j.CountAll(); ct != 0 {
+		t.Errorf("Count should be 0 after Free, was %v", ct)
+	}
+}
+
+func TestJudyLOfJudyL(t *testing.T) {
+
+	j := JudyLOfJudyL{}
+	defer j.Free()
+
+	var row, col uint64
+	for row = 0; row < 20; row++ {
+		for col = 0; col < 20; col += row + 1 {
+			j.GetOrCreate(row).Insert(col, row*100+col)
+		}
+	}
+
+	if ct := j.CountAll(); ct != 20 {
+		t.Errorf("Count should be 20, was %v", ct)
+	}
+	if v, ok := j.Get(3).Get(8); !ok || v != 308 {
+		t.Errorf("Get(3).Get(8) should be 308, was %v,%v", v, ok)
+	}
+	if ct := j.Get(0).CountAll(); ct != 20 {
+		t.Errorf("Row 0 count should be 20, was %v", ct)
+	}
+	if idx, c, ok := j.Last(math.MaxUint64); !ok || idx != 19 || c.CountAll() != 1 {
+		t.Errorf("Last should be row 19 with one column, was %v,%v", idx, ok)
+	}
+	if idx, _, ok := j.Prev(19); !ok || idx != 18 {
+		t.Errorf("Prev(19) should be 18, was %v,%v", idx, ok)
+	}
+	if idx, _, ok := j.Next(5); !ok || idx != 6 {
+		t.Errorf("Next(5) should be 6, was %v,%v", idx, ok)
+	}
+
+	var total uint64
+	for _, c := range j.All() {
+		total += c.CountAll()
+	}
+	if cells := j.CountFrom(0, math.MaxUint64); cells != 20 || total == 0 {
+		t.Errorf("Traversal should visit every row, visited %v cells", total)
+	}
+
+	if !j.Delete(0) || j.Get(0) != nil {
+		t.Error("Delete(0) should remove the row")
+	}
+	if j.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
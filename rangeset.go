@@ -0,0 +1,185 @@
+package judy
+
+import (
+	"iter"
+	"math"
+)
+
+// A RangeSet is a Judy1 array viewed as a set of runs: maximal ranges of consecutive indexes present. The gaps are
+// the maximal ranges of absent indexes between them. Runs and gaps are found with First and NextEmpty (and their
+// reverse and empty counterparts), so walking the runs costs a few searches per run however long the runs are.
+// The default value of this struct is a valid empty set.
+//
+//    r := RangeSet{}
+//    defer r.Free()
+//
+//    r.AddRange(100, 199)
+//    r.AddRange(200, 299)
+//    for lo, hi := range r.Runs() {
+//        fmt.Println(lo, hi) // 100 299
+//    }
+//
+// NOTE: The underlying Judy1 array allocates memory outside of the Go runtime. It is very important that you call
+// Free() on a RangeSet after using it to prevent memory leaks.
+type RangeSet struct {
+	array Judy1
+}
+
+// Add index to the set.
+// Return true if index was previously absent, otherwise false.
+func (r *RangeSet) Add(index uint64) bool {
+	return r.array.Set(index)
+}
+
+// Remove index from the set.
+// Return true if index was previously present, otherwise false.
+func (r *RangeSet) Remove(index uint64) bool {
+	return r.array.Unset(index)
+}
+
+// Contains tests if index is in the set.
+func (r *RangeSet) Contains(index uint64) bool {
+	return r.array.Test(index)
+}
+
+// AddRange adds every index from lo to hi (inclusive) to the set, in a single call into C that jumps over the runs
+// already in the set, so the cost is proportional to the number of indexes added. Nothing is added if lo > hi.
+func (r *RangeSet) AddRange(lo, hi uint64) {
+	if lo <= hi {
+		r.array.setRange(lo, hi)
+	}
+}
+
+// RemoveRange removes every index from lo to hi (inclusive) from the set, in a single call into C that only visits
+// the indexes in the set, so the cost is proportional to the number of indexes removed. Nothing is removed if
+// lo > hi.
+func (r *RangeSet) RemoveRange(lo, hi uint64) {
+	if lo <= hi {
+		r.array.unsetRange(lo, hi)
+	}
+}
+
+// AddRuns adds every run of runs to the set, such as the Runs of another RangeSet. Only the indexes not already in
+// the set are visited.
+func (r *RangeSet) AddRuns(runs iter.Seq2[uint64, uint64]) {
+	for lo, hi := range runs {
+		r.AddRange(lo, hi)
+	}
+}
+
+// Count the number of indexes in the set.
+func (r *RangeSet) CountAll() uint64 {
+	return r.array.CountAll()
+}
+
+// Count the number of indexes in the set between indexA and indexB (inclusive).
+func (r *RangeSet) CountFrom(indexA, indexB uint64) uint64 {
+	return r.array.CountFrom(indexA, indexB)
+}
+
+// Count the number of runs in the set.
+func (r *RangeSet) CountRuns() uint64 {
+	var n uint64
+	for range r.Runs() {
+		n++
+	}
+	return n
+}
+
+// Runs returns an iterator over the runs of the set, as their first and last index, in ascending order. Each run
+// is found with First and NextEmpty, so the indexes inside a run are not visited. Each step resumes after the last
+// run yielded, so the set may be modified while iterating.
+func (r *RangeSet) Runs() iter.Seq2[uint64, uint64] {
+	return func(yield func(uint64, uint64) bool) {
+		for lo, ok := r.array.First(0); ok; {
+			hi := r.runEnd(lo)
+			if !yield(lo, hi) || hi == math.MaxUint64 {
+				return
+			}
+			lo, ok = r.array.First(hi + 1)
+		}
+	}
+}
+
+// Run returns the run containing index
+//   returns (lo, hi, true) if index is in the set
+//   returns (_, _, false) if index is not in the set
+func (r *RangeSet) Run(index uint64) (uint64, uint64, bool) {
+	if !r.array.Test(index) {
+		return 0, 0, false
+	}
+	return r.runStart(index), r.runEnd(index), true
+}
+
+// GapAfter returns the gap containing the first absent index that is equal to or greater than the passed index.
+// The gap may start before index if index is absent.
+//   returns (lo, hi, true) if there is an absent index at or after index
+//   returns (_, _, false) if every index from index up is in the set
+func (r *RangeSet) GapAfter(index uint64) (uint64, uint64, bool) {
+	empty, ok := r.array.FirstEmpty(index)
+	if !ok {
+		return 0, 0, false
+	}
+	return r.gapStart(empty), r.gapEnd(empty), true
+}
+
+// GapBefore returns the gap containing the last absent index that is equal to or less than the passed index.
+// The gap may end after index if index is absent.
+//   returns (lo, hi, true) if there is an absent index at or before index
+//   returns (_, _, false) if every index from index down is in the set
+func (r *RangeSet) GapBefore(index uint64) (uint64, uint64, bool) {
+	empty, ok := r.array.LastEmpty(index)
+	if !ok {
+		return 0, 0, false
+	}
+	return r.gapStart(empty), r.gapEnd(empty), true
+}
+
+// runStart returns the first index of the run containing index, which must be present.
+func (r *RangeSet) runStart(index uint64) uint64 {
+	if empty, ok := r.array.PrevEmpty(index); ok {
+		return empty + 1
+	}
+	return 0
+}
+
+// runEnd returns the last index of the run containing index, which must be present.
+func (r *RangeSet) runEnd(index uint64) uint64 {
+	if empty, ok := r.array.NextEmpty(index); ok {
+		return empty - 1
+	}
+	return math.MaxUint64
+}
+
+// gapStart returns the first index of the gap containing index, which must be absent.
+func (r *RangeSet) gapStart(index uint64) uint64 {
+	if present, ok := r.array.Prev(index); ok {
+		return present + 1
+	}
+	return 0
+}
+
+// gapEnd returns the last index of the gap containing index, which must be absent.
+func (r *RangeSet) gapEnd(index uint64) uint64 {
+	if present, ok := r.array.Next(index); ok {
+		return present - 1
+	}
+	return math.MaxUint64
+}
+
+// Array returns the underlying Judy1 array, for the operations RangeSet does not wrap (such as the set algebra of
+// Expr). Setting and unsetting indexes through it is allowed.
+func (r *RangeSet) Array() *Judy1 {
+	return &r.array
+}
+
+// Return the number of bytes of memory currently in use by the set.
+func (r *RangeSet) MemoryUsed() uint64 {
+	return r.array.MemoryUsed()
+}
+
+// Free the entire set.
+// Return the number of bytes freed.
+func (r *RangeSet) Free() uint64 {
+	return r.array.Free()
+}
@@ -0,0 +1,176 @@
+package judy
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestEmptyRangeSet(t *testing.T) {
+
+	r := RangeSet{}
+	if n := r.Free(); n != 0 {
+		t.Errorf("Free should return 0, returned %v", n)
+	}
+	for lo, hi := range r.Runs() {
+		t.Errorf("An empty set should have no runs, had %v-%v", lo, hi)
+	}
+	if lo, hi, ok := r.GapAfter(5); !ok || lo != 0 || hi != math.MaxUint64 {
+		t.Errorf("The gap of an empty set should be everything, was %v-%v,%v", lo, hi, ok)
+	}
+	if _, _, ok := r.Run(5); ok {
+		t.Error("An empty set should have no run containing 5")
+	}
+}
+
+func TestRangeSetRuns(t *testing.T) {
+
+	r := RangeSet{}
+	defer r.Free()
+
+	r.AddRange(100, 199)
+	r.AddRange(200, 299)
+	r.AddRange(500, 500)
+	r.AddRange(10, 5)
+	r.Add(0)
+	r.AddRange(math.MaxUint64-9, math.MaxUint64)
+	r.RemoveRange(150, 159)
+
+	expected := [][2]uint64{{0, 0}, {100, 149}, {160, 299}, {500, 500}, {math.MaxUint64 - 9, math.MaxUint64}}
+	var got [][2]uint64
+	for lo, hi := range r.Runs() {
+		got = append(got, [2]uint64{lo, hi})
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Runs should be %v, was %v", expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("Run %v should be %v, was %v", i, expected[i], got[i])
+		}
+	}
+	if n := r.CountRuns(); n != 5 {
+		t.Errorf("CountRuns should be 5, was %v", n)
+	}
+	if ct := r.CountAll(); ct != 1+50+140+1+10 {
+		t.Errorf("CountAll should be 202, was %v", ct)
+	}
+	if ct := r.CountFrom(100, 199); ct != 90 {
+		t.Errorf("CountFrom(100, 199) should be 90, was %v", ct)
+	}
+
+	if lo, hi, ok := r.Run(200); !ok || lo != 160 || hi != 299 {
+		t.Errorf("Run(200) should be 160-299, was %v-%v,%v", lo, hi, ok)
+	}
+	if lo, hi, ok := r.Run(0); !ok || lo != 0 || hi != 0 {
+		t.Errorf("Run(0) should be 0-0, was %v-%v,%v", lo, hi, ok)
+	}
+	if lo, hi, ok := r.Run(math.MaxUint64); !ok || lo != math.MaxUint64-9 || hi != math.MaxUint64 {
+		t.Errorf("Run(MaxUint64) should be the last run, was %v-%v,%v", lo, hi, ok)
+	}
+	if _, _, ok := r.Run(155); ok {
+		t.Error("Run(155) should not be found")
+	}
+
+	if lo, hi, ok := r.GapAfter(120); !ok || lo != 150 || hi != 159 {
+		t.Errorf("GapAfter(120) should be 150-159, was %v-%v,%v", lo, hi, ok)
+	}
+	if lo, hi, ok := r.GapAfter(155); !ok || lo != 150 || hi != 159 {
+		t.Errorf("GapAfter(155) should be 150-159, was %v-%v,%v", lo, hi, ok)
+	}
+	if lo, hi, ok := r.GapBefore(120); !ok || lo != 1 || hi != 99 {
+		t.Errorf("GapBefore(120) should be 1-99, was %v-%v,%v", lo, hi, ok)
+	}
+	if lo, hi, ok := r.GapAfter(500); !ok || lo != 501 || hi != math.MaxUint64-10 {
+		t.Errorf("GapAfter(500) should be 501-(MaxUint64-10), was %v-%v,%v", lo, hi, ok)
+	}
+	if _, _, ok := r.GapAfter(math.MaxUint64 - 5); ok {
+		t.Error("GapAfter in the last run should not be found")
+	}
+	if _, _, ok := r.GapBefore(0); ok {
+		t.Error("GapBefore(0) should not be found")
+	}
+
+	if !r.Array().Test(160) || r.MemoryUsed() == 0 {
+		t.Error("Array should be the underlying Judy1 array")
+	}
+}
+
+func TestRangeSetLargeRanges(t *testing.T) {
+
+	r := RangeSet{}
+	defer r.Free()
+
+	r.Add(3)
+	r.Add(1 << 40)
+	r.Add(math.MaxUint64)
+	// only the three indexes present are visited
+	r.RemoveRange(0, math.MaxUint64)
+	if ct := r.CountAll(); ct != 0 {
+		t.Errorf("RemoveRange(0, MaxUint64) should empty the set, CountAll was %v", ct)
+	}
+
+	// adding runs that are already present jumps over them
+	r.AddRange(0, 1<<16)
+	r.AddRange(math.MaxUint64-9, math.MaxUint64)
+	other := RangeSet{}
+	defer other.Free()
+	other.AddRange(10, 1<<16+10)
+	other.AddRuns(r.Runs())
+	if ct := other.CountAll(); ct != 1<<16+11+10 {
+		t.Errorf("AddRuns should leave %v indexes, left %v", 1<<16+11+10, ct)
+	}
+	if ct := other.CountRuns(); ct != 2 {
+		t.Errorf("AddRuns should leave 2 runs, left %v", ct)
+	}
+}
+
+func TestRangeSetRandom(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(1))
+	r := RangeSet{}
+	defer r.Free()
+
+	present := make([]bool, 2000)
+	for i := 0; i < 100; i++ {
+		lo := rng.Intn(len(present))
+		hi := lo + rng.Intn(50)
+		if hi >= len(present) {
+			hi = len(present) - 1
+		}
+		add := rng.Intn(3) > 0
+		if add {
+			r.AddRange(uint64(lo), uint64(hi))
+		} else {
+			r.RemoveRange(uint64(lo), uint64(hi))
+		}
+		for j := lo; j <= hi; j++ {
+			present[j] = add
+		}
+	}
+
+	// runs are maximal, and cover exactly the indexes present
+	seen := make([]bool, len(present))
+	prevHi := -2
+	for lo, hi := range r.Runs() {
+		if int(lo) <= prevHi+1 {
+			t.Fatalf("Run %v-%v should not touch the previous run ending at %v", lo, hi, prevHi)
+		}
+		for j := lo; j <= hi; j++ {
+			seen[j] = true
+		}
+		prevHi = int(hi)
+	}
+	for i := range present {
+		if seen[i] != present[i] {
+			t.Fatalf("Index %v should be %v in the runs", i, present[i])
+		}
+	}
+
+	copied := RangeSet{}
+	defer copied.Free()
+	copied.AddRuns(r.Runs())
+	if copied.CountAll() != r.CountAll() || copied.CountRuns() != r.CountRuns() {
+		t.Error("AddRuns should copy every run")
+	}
+}
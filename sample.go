@@ -0,0 +1,141 @@
+package judy
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Draw k distinct indexes uniformly at random from the Judy1 array (sampling without replacement).
+// The sample is returned in ascending index order. If k >= CountAll() every index present is returned.
+//
+// Positions are chosen with Floyd's algorithm and resolved with ByCount, so the cost is O(k log n) and the array is
+// never scanned.
+func (j *Judy1) Sample(rng *rand.Rand, k int) []uint64 {
+	positions := samplePositions(rng, j.CountAll(), k)
+	defer positions.Free()
+
+	keys := make([]uint64, 0, positions.CountAll())
+	for p, ok := positions.First(0); ok; p, ok = positions.Next(p) {
+		idx, _ := j.ByCount(p + 1)
+		keys = append(keys, idx)
+	}
+	return keys
+}
+
+// Draw k indexes uniformly at random from the Judy1 array (sampling with replacement), so the same index may be
+// returned more than once. The sample is returned in the order it was drawn, and is empty if the array is empty.
+func (j *Judy1) SampleWithReplacement(rng *rand.Rand, k int) []uint64 {
+	n := j.CountAll()
+	if n == 0 || k <= 0 {
+		return nil
+	}
+
+	keys := make([]uint64, k)
+	for i := range keys {
+		keys[i], _ = j.ByCount(randUint64n(rng, n) + 1)
+	}
+	return keys
+}
+
+// Draw one index uniformly at random from the indexes present in the Judy1 array between lo and hi (inclusive).
+//
+//   returns uint64 - random index in [lo, hi] (unless return false)
+//           bool   - true if successful, false if no index is present between lo and hi
+func (j *Judy1) RandomInRange(rng *rand.Rand, lo, hi uint64) (uint64, bool) {
+	n := j.CountFrom(lo, hi)
+	if n == 0 {
+		return 0, false
+	}
+	var before uint64
+	if lo > 0 {
+		before = j.Rank(lo - 1)
+	}
+	return j.Select(before + randUint64n(rng, n))
+}
+
+// Draw k distinct index/value pairs uniformly at random from the JudyL array (sampling without replacement).
+// The sample is returned in ascending index order. If k >= CountAll() every pair present is returned.
+//
+// Positions are chosen with Floyd's algorithm and resolved with ByCount, so the cost is O(k log n) and the array is
+// never scanned.
+func (j *JudyL) Sample(rng *rand.Rand, k int) (keys, values []uint64) {
+	positions := samplePositions(rng, j.CountAll(), k)
+	defer positions.Free()
+
+	n := positions.CountAll()
+	keys, values = make([]uint64, 0, n), make([]uint64, 0, n)
+	for p, ok := positions.First(0); ok; p, ok = positions.Next(p) {
+		idx, val, _ := j.ByCount(p + 1)
+		keys, values = append(keys, idx), append(values, val)
+	}
+	return keys, values
+}
+
+// Draw k index/value pairs uniformly at random from the JudyL array (sampling with replacement), so the same pair
+// may be returned more than once. The sample is returned in the order it was drawn, and is empty if the array is
+// empty.
+func (j *JudyL) SampleWithReplacement(rng *rand.Rand, k int) (keys, values []uint64) {
+	n := j.CountAll()
+	if n == 0 || k <= 0 {
+		return nil, nil
+	}
+
+	keys, values = make([]uint64, k), make([]uint64, k)
+	for i := range keys {
+		keys[i], values[i], _ = j.ByCount(randUint64n(rng, n) + 1)
+	}
+	return keys, values
+}
+
+// Draw one index/value pair uniformly at random from the indexes present in the JudyL array between lo and hi
+// (inclusive).
+//
+//   returns uint64 - random index in [lo, hi] (unless return false)
+//           uint64 - value of that index (unless return false)
+//           bool   - true if successful, false if no index is present between lo and hi
+func (j *JudyL) RandomInRange(rng *rand.Rand, lo, hi uint64) (uint64, uint64, bool) {
+	n := j.CountFrom(lo, hi)
+	if n == 0 {
+		return 0, 0, false
+	}
+	var before uint64
+	if lo > 0 {
+		before = j.Rank(lo - 1)
+	}
+	return j.Select(before + randUint64n(rng, n))
+}
+
+// samplePositions chooses min(k, n) distinct 0-based positions out of n using Floyd's algorithm.
+// The positions are returned as a Judy1 array, which the caller must free.
+func samplePositions(rng *rand.Rand, n uint64, k int) Judy1 {
+	var positions Judy1
+	if k <= 0 || n == 0 {
+		return positions
+	}
+	if uint64(k) >= n {
+		for p := uint64(0); p < n; p++ {
+			positions.Set(p)
+		}
+		return positions
+	}
+
+	for i := n - uint64(k); i < n; i++ {
+		if !positions.Set(randUint64n(rng, i+1)) {
+			positions.Set(i)
+		}
+	}
+	return positions
+}
+
+// randUint64n returns a uniform random number in [0, n). n must be greater than zero.
+func randUint64n(rng *rand.Rand, n uint64) uint64 {
+	if n <= math.MaxInt64 {
+		return uint64(rng.Int63n(int64(n)))
+	}
+	for {
+		// n is more than half the range, so each draw is accepted with probability > 1/2
+		if v := rng.Uint64(); v < n {
+			return v
+		}
+	}
+}
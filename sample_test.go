@@ -0,0 +1,212 @@
+package judy
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestJudy1Sample(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	rng := rand.New(rand.NewSource(1))
+
+	if s := j.Sample(rng, 5); len(s) != 0 {
+		t.Errorf("Sample of an empty array should be empty, was %v", s)
+	}
+
+	var i uint64
+	for i = 0; i < 1000; i++ {
+		j.Set(i * 7)
+	}
+
+	for loops := 0; loops < 100; loops++ {
+		s := j.Sample(rng, 50)
+		if len(s) != 50 {
+			t.Fatalf("Sample should return 50 indexes, returned %v", len(s))
+		}
+		for n, idx := range s {
+			if !j.Test(idx) {
+				t.Errorf("Sampled index %v is not present", idx)
+			}
+			if n > 0 && s[n-1] >= idx {
+				t.Errorf("Sample should be ascending and distinct, was %v", s)
+			}
+		}
+	}
+
+	if s := j.Sample(rng, 2000); len(s) != 1000 || s[0] != 0 || s[999] != 999*7 {
+		t.Errorf("Sample larger than the array should return every index, returned %v", len(s))
+	}
+	if s := j.Sample(rng, 0); len(s) != 0 {
+		t.Errorf("Sample of 0 should be empty, was %v", s)
+	}
+}
+
+func TestJudy1SampleUniform(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	rng := rand.New(rand.NewSource(2))
+
+	var i uint64
+	for i = 0; i < 10; i++ {
+		j.Set(i << 40)
+	}
+
+	counts := make(map[uint64]int)
+	for loops := 0; loops < 10000; loops++ {
+		for _, idx := range j.Sample(rng, 3) {
+			counts[idx]++
+		}
+	}
+	for i = 0; i < 10; i++ {
+		// each index is expected 3000 times
+		if ct := counts[i<<40]; ct < 2700 || ct > 3300 {
+			t.Errorf("Index %v sampled %v times, expected about 3000", i<<40, ct)
+		}
+	}
+}
+
+func TestJudy1SampleWithReplacement(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	rng := rand.New(rand.NewSource(3))
+
+	if s := j.SampleWithReplacement(rng, 5); len(s) != 0 {
+		t.Errorf("Sample of an empty array should be empty, was %v", s)
+	}
+
+	j.Set(10)
+	j.Set(math.MaxUint64)
+
+	s := j.SampleWithReplacement(rng, 100)
+	if len(s) != 100 {
+		t.Fatalf("Sample should return 100 indexes, returned %v", len(s))
+	}
+	var ct int
+	for _, idx := range s {
+		if idx != 10 && idx != math.MaxUint64 {
+			t.Errorf("Sampled index %v is not present", idx)
+		}
+		if idx == 10 {
+			ct++
+		}
+	}
+	if ct == 0 || ct == 100 {
+		t.Errorf("Sample with replacement should draw both indexes, drew 10 %v times", ct)
+	}
+}
+
+func TestJudy1RandomInRange(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	rng := rand.New(rand.NewSource(4))
+
+	if _, ok := j.RandomInRange(rng, 0, math.MaxUint64); ok {
+		t.Error("RandomInRange of an empty array should not be found")
+	}
+
+	var i uint64
+	for i = 0; i < 100; i++ {
+		j.Set(i * 10)
+	}
+	j.Set(math.MaxUint64)
+
+	for loops := 0; loops < 1000; loops++ {
+		if idx, ok := j.RandomInRange(rng, 95, 205); !ok || idx < 100 || idx > 200 || !j.Test(idx) {
+			t.Fatalf("RandomInRange(95, 205) should be a present index in [100, 200], was %v,%v", idx, ok)
+		}
+	}
+	if idx, ok := j.RandomInRange(rng, 0, 5); !ok || idx != 0 {
+		t.Errorf("RandomInRange(0, 5) should be 0,true was %v,%v", idx, ok)
+	}
+	if idx, ok := j.RandomInRange(rng, 1000, math.MaxUint64); !ok || idx != math.MaxUint64 {
+		t.Errorf("RandomInRange(1000, MaxUint64) should be MaxUint64,true was %v,%v", idx, ok)
+	}
+	if _, ok := j.RandomInRange(rng, 991, 999); ok {
+		t.Error("RandomInRange(991, 999) should not be found")
+	}
+}
+
+func TestJudyLSample(t *testing.T) {
+
+	j := JudyL{}
+	defer j.Free()
+
+	rng := rand.New(rand.NewSource(5))
+
+	if keys, values := j.Sample(rng, 5); len(keys) != 0 || len(values) != 0 {
+		t.Errorf("Sample of an empty array should be empty, was %v, %v", keys, values)
+	}
+
+	var i uint64
+	for i = 0; i < 1000; i++ {
+		j.Insert(i*7, i)
+	}
+
+	keys, values := j.Sample(rng, 50)
+	if len(keys) != 50 || len(values) != 50 {
+		t.Fatalf("Sample should return 50 pairs, returned %v, %v", len(keys), len(values))
+	}
+	for n := range keys {
+		if values[n]*7 != keys[n] {
+			t.Errorf("Sampled pair %v,%v does not match the array", keys[n], values[n])
+		}
+		if n > 0 && keys[n-1] >= keys[n] {
+			t.Errorf("Sample should be ascending and distinct, was %v", keys)
+		}
+	}
+
+	keys, values = j.SampleWithReplacement(rng, 20)
+	if len(keys) != 20 || len(values) != 20 {
+		t.Fatalf("Sample should return 20 pairs, returned %v, %v", len(keys), len(values))
+	}
+	for n := range keys {
+		if values[n]*7 != keys[n] {
+			t.Errorf("Sampled pair %v,%v does not match the array", keys[n], values[n])
+		}
+	}
+}
+
+func TestJudyLRandomInRange(t *testing.T) {
+
+	j := JudyL{}
+	defer j.Free()
+
+	rng := rand.New(rand.NewSource(6))
+
+	var i uint64
+	for i = 0; i < 100; i++ {
+		j.Insert(i*10, i)
+	}
+
+	for loops := 0; loops < 1000; loops++ {
+		if idx, val, ok := j.RandomInRange(rng, 95, 205); !ok || idx < 100 || idx > 200 || val*10 != idx {
+			t.Fatalf("RandomInRange(95, 205) should be a present pair in [100, 200], was %v,%v,%v", idx, val, ok)
+		}
+	}
+	if _, _, ok := j.RandomInRange(rng, 991, math.MaxUint64); ok {
+		t.Error("RandomInRange(991, MaxUint64) should not be found")
+	}
+}
+
+func TestRandUint64n(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(7))
+
+	for _, n := range []uint64{1, 2, 1000, math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64} {
+		for loops := 0; loops < 100; loops++ {
+			if v := randUint64n(rng, n); v >= n {
+				t.Fatalf("randUint64n(%v) returned %v", n, v)
+			}
+		}
+	}
+}
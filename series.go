@@ -0,0 +1,181 @@
+package judy
+
+import (
+	"iter"
+	"math"
+)
+
+// A Series is a time series of float64 samples keyed by timestamp in Unix nanoseconds, stored in a JudyL. The
+// timestamps are encoded with Int64Key so that times before 1970 sort first, and the values as their bits.
+// The default value of this struct is a valid empty series.
+//
+//    s := judy.Series{}
+//    defer s.Free()
+//
+//    s.Append(time.Now().UnixNano(), 0.75)
+//    for b := range s.Downsample(from, to, int64(time.Minute)) {
+//        fmt.Println(b.Start, b.Min, b.Max, b.Mean())
+//    }
+//    s.Trim(time.Now().Add(-24 * time.Hour).UnixNano())
+//
+// NOTE: The underlying JudyL array allocates memory outside of the Go runtime. It is very important that you call
+// Free() on a Series after using it to prevent memory leaks.
+type Series struct {
+	array JudyL
+}
+
+// A Bucket summarizes the samples of a Series in the interval from Start to Start plus the bucket width.
+type Bucket struct {
+	Start         int64
+	Min, Max, Sum float64
+	Count         uint64
+}
+
+// Mean returns the mean of the samples in the bucket.
+func (b Bucket) Mean() float64 {
+	return b.Sum / float64(b.Count)
+}
+
+// Append a sample at timestamp ts. Samples may be appended out of order; a sample at a timestamp that is already
+// present replaces it.
+func (s *Series) Append(ts int64, value float64) {
+	s.array.Insert(Int64Key{}.EncodeKey(ts), math.Float64bits(value))
+}
+
+// Get the sample at timestamp ts
+//   returns (value, true) if there is a sample at ts
+//   returns (_, false) if there is no sample at ts
+func (s *Series) Get(ts int64) (float64, bool) {
+	w, ok := s.array.Get(Int64Key{}.EncodeKey(ts))
+	return math.Float64frombits(w), ok
+}
+
+// Count the number of samples in the series.
+func (s *Series) CountAll() uint64 {
+	return s.array.CountAll()
+}
+
+// Count the number of samples from timestamp from to timestamp to (inclusive).
+func (s *Series) CountFrom(from, to int64) uint64 {
+	if from > to {
+		return 0
+	}
+	return s.array.CountFrom(Int64Key{}.EncodeKey(from), Int64Key{}.EncodeKey(to))
+}
+
+// First returns the earliest sample.
+//   returns (ts, value, true) if the series is not empty
+//   returns (_, _, false) if the series is empty
+func (s *Series) First() (int64, float64, bool) {
+	return s.sample(s.array.First(0))
+}
+
+// Last returns the latest sample.
+//   returns (ts, value, true) if the series is not empty
+//   returns (_, _, false) if the series is empty
+func (s *Series) Last() (int64, float64, bool) {
+	return s.sample(s.array.Last(math.MaxUint64))
+}
+
+// At returns the sample nearest to timestamp ts, found with Last and First around it; of two samples equally near,
+// the earlier is returned.
+//   returns (sample ts, value, true) if the series is not empty
+//   returns (_, _, false) if the series is empty
+func (s *Series) At(ts int64) (int64, float64, bool) {
+	index := Int64Key{}.EncodeKey(ts)
+	before, bv, bok := s.array.Last(index)
+	after, av, aok := s.array.First(index)
+	if bok && (!aok || index-before <= after-index) {
+		return s.sample(before, bv, true)
+	}
+	return s.sample(after, av, aok)
+}
+
+func (s *Series) sample(index, w uint64, ok bool) (int64, float64, bool) {
+	if !ok {
+		return 0, 0, false
+	}
+	return Int64Key{}.DecodeKey(index), math.Float64frombits(w), true
+}
+
+// Range returns an iterator over the samples from timestamp from to timestamp to (inclusive), in time order. Each
+// step resumes with Next, so samples may be appended and deleted while iterating.
+func (s *Series) Range(from, to int64) iter.Seq2[int64, float64] {
+	return func(yield func(int64, float64) bool) {
+		if from > to {
+			return
+		}
+		last := Int64Key{}.EncodeKey(to)
+		for index, w, ok := s.array.First(Int64Key{}.EncodeKey(from)); ok && index <= last; index, w, ok = s.array.Next(index) {
+			if !yield(Int64Key{}.DecodeKey(index), math.Float64frombits(w)) {
+				return
+			}
+		}
+	}
+}
+
+// Downsample returns an iterator over the samples from timestamp from to timestamp to (inclusive), summarized into
+// buckets of width nanoseconds starting at from, in time order. Buckets without samples are skipped.
+// It panics if width is not positive.
+func (s *Series) Downsample(from, to, width int64) iter.Seq[Bucket] {
+	if width <= 0 {
+		panic("judy: Series.Downsample width must be positive")
+	}
+	return func(yield func(Bucket) bool) {
+		var b Bucket
+		for ts, v := range s.Range(from, to) {
+			// the offset from from is computed as uint64, as it can exceed math.MaxInt64
+			start := from + int64((uint64(ts)-uint64(from))/uint64(width)*uint64(width))
+			if b.Count > 0 && start != b.Start {
+				if !yield(b) {
+					return
+				}
+				b.Count = 0
+			}
+			if b.Count == 0 {
+				b = Bucket{Start: start, Min: v, Max: v}
+			}
+			b.Min, b.Max = min(b.Min, v), max(b.Max, v)
+			b.Sum += v
+			b.Count++
+		}
+		if b.Count > 0 {
+			yield(b)
+		}
+	}
+}
+
+// Delete the sample at timestamp ts.
+// Returns true if successful. Returns false if there was no sample at ts.
+func (s *Series) Delete(ts int64) bool {
+	return s.array.Delete(Int64Key{}.EncodeKey(ts))
+}
+
+// DeleteRange deletes the samples from timestamp from to timestamp to (inclusive) with JudyL.DeleteRange.
+// Return the number of samples deleted.
+func (s *Series) DeleteRange(from, to int64) uint64 {
+	if from > to {
+		return 0
+	}
+	return s.array.DeleteRange(Int64Key{}.EncodeKey(from), Int64Key{}.EncodeKey(to))
+}
+
+// Trim deletes the samples before timestamp before, for retention.
+// Return the number of samples deleted.
+func (s *Series) Trim(before int64) uint64 {
+	if before == math.MinInt64 {
+		return 0
+	}
+	return s.DeleteRange(math.MinInt64, before-1)
+}
+
+// Return the number of bytes of memory currently in use by the series.
+func (s *Series) MemoryUsed() uint64 {
+	return s.array.MemoryUsed()
+}
+
+// Free the entire series.
+// Return the number of bytes freed.
+func (s *Series) Free() uint64 {
+	return s.array.Free()
+}
@@ -0,0 +1,167 @@
+package judy
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestEmptySeries(t *testing.T) {
+
+	s := Series{}
+	if r := s.Free(); r != 0 {
+		t.Errorf("Free should return 0, returned %v", r)
+	}
+	if _, _, ok := s.Last(); ok {
+		t.Error("Last on an empty series should not be found")
+	}
+	if _, _, ok := s.At(0); ok {
+		t.Error("At on an empty series should not be found")
+	}
+}
+
+func TestSeriesAppend(t *testing.T) {
+
+	s := Series{}
+	defer s.Free()
+
+	s.Append(100, 1)
+	s.Append(-50, -1)
+	s.Append(300, 3)
+	s.Append(200, 2)
+	s.Append(200, 2.5)
+
+	if v, ok := s.Get(200); !ok || v != 2.5 {
+		t.Errorf("Get(200) should be 2.5, was %v,%v", v, ok)
+	}
+	if ct := s.CountAll(); ct != 4 {
+		t.Errorf("CountAll should be 4, was %v", ct)
+	}
+	if ts, v, ok := s.First(); !ok || ts != -50 || v != -1 {
+		t.Errorf("First should be -50=-1, was %v=%v,%v", ts, v, ok)
+	}
+	if ts, v, ok := s.Last(); !ok || ts != 300 || v != 3 {
+		t.Errorf("Last should be 300=3, was %v=%v,%v", ts, v, ok)
+	}
+	if ct := s.CountFrom(-50, 200); ct != 3 {
+		t.Errorf("CountFrom(-50, 200) should be 3, was %v", ct)
+	}
+
+	var tss []int64
+	for ts := range s.Range(0, 250) {
+		tss = append(tss, ts)
+	}
+	if !slices.Equal(tss, []int64{100, 200}) {
+		t.Errorf("Range(0, 250) should be [100 200], was %v", tss)
+	}
+	for range s.Range(250, 0) {
+		t.Error("Range with from > to should be empty")
+	}
+
+	tests := []struct {
+		ts, expected int64
+	}{
+		{-1000, -50},
+		{24, -50},
+		{25, -50}, // equally near, the earlier wins
+		{26, 100},
+		{200, 200},
+		{260, 300},
+		{math.MaxInt64, 300},
+	}
+	for _, tt := range tests {
+		if ts, _, ok := s.At(tt.ts); !ok || ts != tt.expected {
+			t.Errorf("At(%v) should be %v, was %v,%v", tt.ts, tt.expected, ts, ok)
+		}
+	}
+
+	if !s.Delete(100) || s.Delete(100) {
+		t.Error("Delete(100) should succeed once")
+	}
+	if s.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
+
+func TestSeriesDownsample(t *testing.T) {
+
+	s := Series{}
+	defer s.Free()
+
+	for ts := int64(0); ts < 100; ts++ {
+		s.Append(ts, float64(ts%7))
+	}
+
+	var buckets []Bucket
+	for b := range s.Downsample(5, 44, 10) {
+		buckets = append(buckets, b)
+	}
+	if len(buckets) != 4 {
+		t.Fatalf("Downsample should return 4 buckets, returned %v", len(buckets))
+	}
+	// 5..14: 5 6 0 1 2 3 4 5 6 0
+	if b := buckets[0]; b.Start != 5 || b.Count != 10 || b.Min != 0 || b.Max != 6 || b.Sum != 32 {
+		t.Errorf("The first bucket should be 5 with 10 samples, min 0, max 6 and sum 32, was %+v", b)
+	}
+	if b := buckets[3]; b.Start != 35 || b.Count != 10 {
+		t.Errorf("The last bucket should be 35 with 10 samples, was %+v", b)
+	}
+	if m := buckets[0].Mean(); m != 3.2 {
+		t.Errorf("The mean of the first bucket should be 3.2, was %v", m)
+	}
+
+	// gaps are skipped
+	s.DeleteRange(20, 39)
+	var starts []int64
+	for b := range s.Downsample(0, 99, 10) {
+		starts = append(starts, b.Start)
+	}
+	if !slices.Equal(starts, []int64{0, 10, 40, 50, 60, 70, 80, 90}) {
+		t.Errorf("Downsample should skip empty buckets, returned %v", starts)
+	}
+
+	// buckets far from from
+	s.Append(math.MaxInt64, 1)
+	for b := range s.Downsample(math.MinInt64, math.MaxInt64, math.MaxInt64) {
+		if b.Start != -1 && b.Start != math.MaxInt64-1 {
+			t.Errorf("Buckets of width MaxInt64 from MinInt64 should start at -1 or MaxInt64-1, was %v", b.Start)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Downsample with width 0 should panic")
+		}
+	}()
+	s.Downsample(0, 10, 0)
+}
+
+func TestSeriesTrim(t *testing.T) {
+
+	s := Series{}
+	defer s.Free()
+
+	for ts := int64(-10); ts < 10; ts++ {
+		s.Append(ts, 0)
+	}
+	s.Append(math.MinInt64, 0)
+
+	if n := s.Trim(0); n != 11 {
+		t.Errorf("Trim(0) should delete 11 samples, deleted %v", n)
+	}
+	if ts, _, _ := s.First(); ts != 0 {
+		t.Errorf("The first sample after Trim(0) should be 0, was %v", ts)
+	}
+	if n := s.Trim(math.MinInt64); n != 0 {
+		t.Errorf("Trim(MinInt64) should delete nothing, deleted %v", n)
+	}
+	if n := s.DeleteRange(5, 4); n != 0 {
+		t.Errorf("DeleteRange with from > to should delete nothing, deleted %v", n)
+	}
+	if n := s.DeleteRange(5, math.MaxInt64); n != 5 {
+		t.Errorf("DeleteRange(5, MaxInt64) should delete 5 samples, deleted %v", n)
+	}
+	if ct := s.CountAll(); ct != 5 {
+		t.Errorf("CountAll should be 5, was %v", ct)
+	}
+}
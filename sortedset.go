@@ -0,0 +1,170 @@
+package judy
+
+import (
+	"iter"
+	"math"
+)
+
+// A SortedSet is a set of members ranked by score, like a Redis sorted set (ZSET), for leaderboards and the like.
+// It is made of a JudyL from member to score and a Set128 of keys that hold the score (encoded as by Float64Key) in
+// the high word and the member in the low word, so the Set128 is in score order, ties broken by member. Ranks are
+// counted with CountFrom and located with ByCount. Members are uint64 and scores float64.
+// The default value of this struct is a valid empty set.
+//
+//    z := judy.SortedSet{}
+//    defer z.Free()
+//
+//    z.ZAdd(player, 1200)
+//    rank, _ := z.ZRank(player)
+//    for member, score := range z.ZRange(-10, -1) { // the top 10, lowest score first
+//        ...
+//    }
+//
+// The Set128 keeps one Judy1 per distinct score, and CountFrom and ByCount count those of the lower scores one at a
+// time, so ZRank and ZRange take time in proportion to the number of distinct scores below the rank. They are
+// cheapest when many members share a score.
+//
+// NOTE: The Judy arrays allocate memory outside of the Go runtime. It is very important that you call Free() on a
+// SortedSet after using it to prevent memory leaks.
+type SortedSet struct {
+	scores JudyL
+	order  Set128
+}
+
+// sortedSetScore encodes score so that the encoded scores sort as the scores do. -0 is stored as 0, as they are
+// equal.
+func sortedSetScore(score float64) uint64 {
+	if score == 0 {
+		score = 0
+	}
+	return Float64Key{}.EncodeKey(score)
+}
+
+// ZAdd sets the score of member, adding it if it is not in the set. It panics if score is NaN.
+// Return true if member was added, otherwise false if its score was updated.
+func (z *SortedSet) ZAdd(member uint64, score float64) bool {
+	if math.IsNaN(score) {
+		panic("judy: SortedSet score is NaN")
+	}
+	s := sortedSetScore(score)
+	old, ok := z.scores.Get(member)
+	if ok {
+		if old == s {
+			return false
+		}
+		z.order.Unset(Key128{old, member})
+	}
+	z.scores.Insert(member, s)
+	z.order.Set(Key128{s, member})
+	return !ok
+}
+
+// ZRem removes member from the set.
+// Return true if member was removed, otherwise false if it was not in the set.
+func (z *SortedSet) ZRem(member uint64) bool {
+	s, ok := z.scores.Get(member)
+	if !ok {
+		return false
+	}
+	z.order.Unset(Key128{s, member})
+	return z.scores.Delete(member)
+}
+
+// ZScore returns the score of member
+//   returns (score, true) if member is in the set
+//   returns (_, false) if member is not in the set
+func (z *SortedSet) ZScore(member uint64) (float64, bool) {
+	s, ok := z.scores.Get(member)
+	if !ok {
+		return 0, false
+	}
+	return Float64Key{}.DecodeKey(s), true
+}
+
+// ZCard returns the number of members in the set.
+func (z *SortedSet) ZCard() uint64 {
+	return z.scores.CountAll()
+}
+
+// ZCount returns the number of members with a score between min and max (inclusive).
+func (z *SortedSet) ZCount(min, max float64) uint64 {
+	if !(min <= max) {
+		return 0
+	}
+	return z.order.CountFrom(Key128{sortedSetScore(min), 0}, Key128{sortedSetScore(max), math.MaxUint64})
+}
+
+// ZRank returns the 0-based rank of member in ascending score order, counted with CountFrom.
+//   returns (rank, true) if member is in the set
+//   returns (_, false) if member is not in the set
+func (z *SortedSet) ZRank(member uint64) (uint64, bool) {
+	s, ok := z.scores.Get(member)
+	if !ok {
+		return 0, false
+	}
+	return z.order.CountFrom(Key128{}, Key128{s, member}) - 1, true
+}
+
+// ZRevRank returns the 0-based rank of member in descending score order.
+//   returns (rank, true) if member is in the set
+//   returns (_, false) if member is not in the set
+func (z *SortedSet) ZRevRank(member uint64) (uint64, bool) {
+	rank, ok := z.ZRank(member)
+	if !ok {
+		return 0, false
+	}
+	return z.ZCard() - 1 - rank, true
+}
+
+// ZRange returns an iterator over the members and scores with ranks start to stop (inclusive) in ascending score
+// order. As in Redis, a negative rank counts from the end of the set, so ZRange(0, -1) is the whole set and
+// ZRange(-3, -1) the three highest scores. The first member is located with ByCount.
+func (z *SortedSet) ZRange(start, stop int64) iter.Seq2[uint64, float64] {
+	return func(yield func(uint64, float64) bool) {
+		card := int64(z.ZCard())
+		if start < 0 {
+			start = max(card+start, 0)
+		}
+		if stop < 0 {
+			stop = card + stop
+		}
+		stop = min(stop, card-1)
+		if start > stop {
+			return
+		}
+		key, ok := z.order.ByCount(uint64(start) + 1)
+		for n := stop - start; ok; n-- {
+			if !yield(key.Lo, Float64Key{}.DecodeKey(key.Hi)) || n == 0 {
+				return
+			}
+			key, ok = z.order.Next(key)
+		}
+	}
+}
+
+// ZRangeByScore returns an iterator over the members and scores with a score between min and max (inclusive), in
+// ascending score order.
+func (z *SortedSet) ZRangeByScore(min, max float64) iter.Seq2[uint64, float64] {
+	return func(yield func(uint64, float64) bool) {
+		if !(min <= max) {
+			return
+		}
+		last := sortedSetScore(max)
+		for key, ok := z.order.First(Key128{sortedSetScore(min), 0}); ok && key.Hi <= last; key, ok = z.order.Next(key) {
+			if !yield(key.Lo, Float64Key{}.DecodeKey(key.Hi)) {
+				return
+			}
+		}
+	}
+}
+
+// Return the number of bytes of memory currently in use by the set.
+func (z *SortedSet) MemoryUsed() uint64 {
+	return z.scores.MemoryUsed() + z.order.MemoryUsed()
+}
+
+// Free the entire set.
+// Return the number of bytes freed.
+func (z *SortedSet) Free() uint64 {
+	return z.scores.Free() + z.order.Free()
+}
@@ -0,0 +1,172 @@
+package judy
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestEmptySortedSet(t *testing.T) {
+
+	z := SortedSet{}
+	if r := z.Free(); r != 0 {
+		t.Errorf("Free should return 0, returned %v", r)
+	}
+	if _, ok := z.ZRank(1); ok {
+		t.Error("ZRank on an empty set should not be found")
+	}
+	for range z.ZRange(0, -1) {
+		t.Error("ZRange on an empty set should be empty")
+	}
+}
+
+func TestSortedSet(t *testing.T) {
+
+	z := SortedSet{}
+	defer z.Free()
+
+	if !z.ZAdd(1, 50) || !z.ZAdd(2, -10) || !z.ZAdd(3, 50) || !z.ZAdd(4, 100.5) || !z.ZAdd(math.MaxUint64, -0.5) {
+		t.Error("ZAdd should add new members")
+	}
+	if z.ZAdd(2, 75) || z.ZAdd(2, 75) {
+		t.Error("ZAdd of an existing member should update its score")
+	}
+	if s, ok := z.ZScore(2); !ok || s != 75 {
+		t.Errorf("ZScore(2) should be 75, was %v,%v", s, ok)
+	}
+	if ct := z.ZCard(); ct != 5 {
+		t.Errorf("ZCard should be 5, was %v", ct)
+	}
+
+	// MaxUint64=-0.5 1=50 3=50 2=75 4=100.5
+	tests := []struct {
+		member uint64
+		rank   uint64
+	}{
+		{math.MaxUint64, 0},
+		{1, 1},
+		{3, 2},
+		{2, 3},
+		{4, 4},
+	}
+	for _, tt := range tests {
+		if r, ok := z.ZRank(tt.member); !ok || r != tt.rank {
+			t.Errorf("ZRank(%v) should be %v, was %v,%v", tt.member, tt.rank, r, ok)
+		}
+		if r, ok := z.ZRevRank(tt.member); !ok || r != 4-tt.rank {
+			t.Errorf("ZRevRank(%v) should be %v, was %v,%v", tt.member, 4-tt.rank, r, ok)
+		}
+	}
+
+	ranges := []struct {
+		start, stop int64
+		expected    []uint64
+	}{
+		{0, -1, []uint64{math.MaxUint64, 1, 3, 2, 4}},
+		{1, 2, []uint64{1, 3}},
+		{-2, -1, []uint64{2, 4}},
+		{-100, 0, []uint64{math.MaxUint64}},
+		{3, 100, []uint64{2, 4}},
+		{2, 1, nil},
+		{5, 10, nil},
+	}
+	for _, tt := range ranges {
+		var members []uint64
+		for m := range z.ZRange(tt.start, tt.stop) {
+			members = append(members, m)
+		}
+		if !slices.Equal(members, tt.expected) {
+			t.Errorf("ZRange(%v, %v) should be %v, was %v", tt.start, tt.stop, tt.expected, members)
+		}
+	}
+
+	var members []uint64
+	var scores []float64
+	for m, s := range z.ZRangeByScore(0, 75) {
+		members, scores = append(members, m), append(scores, s)
+	}
+	if !slices.Equal(members, []uint64{1, 3, 2}) || !slices.Equal(scores, []float64{50, 50, 75}) {
+		t.Errorf("ZRangeByScore(0, 75) should be [1 3 2] [50 50 75], was %v %v", members, scores)
+	}
+	for range z.ZRangeByScore(75, 0) {
+		t.Error("ZRangeByScore with min > max should be empty")
+	}
+	if ct := z.ZCount(50, math.Inf(1)); ct != 4 {
+		t.Errorf("ZCount(50, +Inf) should be 4, was %v", ct)
+	}
+	if ct := z.ZCount(-1, 0); ct != 1 {
+		t.Errorf("ZCount(-1, 0) should be 1, was %v", ct)
+	}
+	if ct := z.ZCount(math.NaN(), 100); ct != 0 {
+		t.Errorf("ZCount(NaN, 100) should be 0, was %v", ct)
+	}
+
+	if !z.ZRem(3) || z.ZRem(3) {
+		t.Error("ZRem(3) should succeed once")
+	}
+	if r, _ := z.ZRank(2); r != 2 {
+		t.Errorf("ZRank(2) after ZRem(3) should be 2, was %v", r)
+	}
+	if z.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
+
+func TestSortedSetZeroScores(t *testing.T) {
+
+	z := SortedSet{}
+	defer z.Free()
+
+	// -0 and 0 are the same score
+	z.ZAdd(1, math.Copysign(0, -1))
+	if z.ZAdd(1, 0) {
+		t.Error("ZAdd(1, 0) should update the existing member")
+	}
+	z.ZAdd(2, 0)
+	if ct := z.ZCount(0, 0); ct != 2 {
+		t.Errorf("ZCount(0, 0) should be 2, was %v", ct)
+	}
+	if ct := z.ZCount(math.Copysign(0, -1), math.Copysign(0, -1)); ct != 2 {
+		t.Errorf("ZCount(-0, -0) should be 2, was %v", ct)
+	}
+	if r, ok := z.ZRank(2); !ok || r != 1 {
+		t.Errorf("ZRank(2) should be 1, was %v,%v", r, ok)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("ZAdd with a NaN score should panic")
+		}
+	}()
+	z.ZAdd(3, math.NaN())
+}
+
+func TestSortedSetManyScores(t *testing.T) {
+
+	z := SortedSet{}
+	defer z.Free()
+
+	// members 0-999 with descending scores, ten members per score
+	for m := uint64(0); m < 1000; m++ {
+		z.ZAdd(m, float64(99-m/10))
+	}
+	if r, ok := z.ZRank(0); !ok || r != 990 {
+		t.Errorf("ZRank(0) should be 990, was %v,%v", r, ok)
+	}
+	if r, ok := z.ZRevRank(995); !ok || r != 994 {
+		t.Errorf("ZRevRank(995) should be 994, was %v,%v", r, ok)
+	}
+	var members []uint64
+	for m := range z.ZRange(15, 17) {
+		members = append(members, m)
+	}
+	if !slices.Equal(members, []uint64{985, 986, 987}) {
+		t.Errorf("ZRange(15, 17) should be [985 986 987], was %v", members)
+	}
+	if ct := z.ZCount(10.5, 20); ct != 100 {
+		t.Errorf("ZCount(10.5, 20) should be 100, was %v", ct)
+	}
+	if z.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
@@ -0,0 +1,294 @@
+package judy
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// A Point is a grid point of a SpatialIndex. Z is ignored by 2D indexes.
+type Point struct {
+	X, Y, Z uint32
+}
+
+// A SpatialIndex maps grid points to uint64 values, stored in a JudyL under the Morton (Z-order) key of each point:
+// the bits of the coordinates interleaved into a uint64, so that points close together on the grid are mostly close
+// together in the key order. A 2D index takes 32-bit X and Y coordinates and a 3D index 21-bit X, Y and Z
+// coordinates; each point holds one value. The default value of this struct is a valid empty 2D index.
+//
+//    s := judy.NewSpatialIndex(2)
+//    defer s.Free()
+//
+//    s.Insert(judy.Point{X: 10, Y: 20}, id)
+//    for p, id := range s.Box(judy.Point{X: 0, Y: 0}, judy.Point{X: 15, Y: 30}) {
+//        ...
+//    }
+//
+// A box query walks the key range from the Morton key of its min corner to that of its max corner, and whenever
+// it reaches a key outside the box it jumps with First to BIGMIN, the smallest key in the range that is back inside
+// the box, so it only visits points near the box.
+//
+// NOTE: The underlying JudyL array allocates memory outside of the Go runtime. It is very important that you call
+// Free() on a SpatialIndex after using it to prevent memory leaks.
+type SpatialIndex struct {
+	array  JudyL
+	threeD bool
+}
+
+// approxCountDepth is the number of times ApproxCount halves a box, so it sums CountFrom over up to
+// 2^approxCountDepth key ranges.
+const approxCountDepth = 8
+
+// NewSpatialIndex returns an empty index of 2 or 3 dimensions. It panics for any other number of dimensions.
+func NewSpatialIndex(dims int) *SpatialIndex {
+	if dims != 2 && dims != 3 {
+		panic("judy: SpatialIndex must have 2 or 3 dimensions")
+	}
+	return &SpatialIndex{threeD: dims == 3}
+}
+
+// dims returns the number of dimensions of the index.
+func (s *SpatialIndex) dims() int {
+	if s.threeD {
+		return 3
+	}
+	return 2
+}
+
+// Insert a point and value into the index. If the point was already present, the current value is replaced.
+// It panics if a coordinate of a 3D point does not fit in 21 bits; Get and Delete simply do not find such a point.
+func (s *SpatialIndex) Insert(p Point, value uint64) {
+	s.array.Insert(s.encode(p), value)
+}
+
+// Delete the point from the index.
+// Returns true if successful. Returns false if the point was not present.
+func (s *SpatialIndex) Delete(p Point) bool {
+	return s.fits(p) && s.array.Delete(s.encode(p))
+}
+
+// Get the value of a point in the index
+//   returns (value, true) if the point was found
+//   returns (_, false) if the point was not found
+func (s *SpatialIndex) Get(p Point) (uint64, bool) {
+	if !s.fits(p) {
+		return 0, false
+	}
+	return s.array.Get(s.encode(p))
+}
+
+// Count the number of points in the index.
+func (s *SpatialIndex) CountAll() uint64 {
+	return s.array.CountAll()
+}
+
+// Box returns an iterator over the points and values inside the box from min to max (inclusive on every axis), in
+// Morton key order. The corners may lie outside the coordinates the index can hold, as in a query for every point
+// from Point{} to Point{math.MaxUint32, math.MaxUint32, math.MaxUint32}.
+// The index must not be modified during the iteration.
+func (s *SpatialIndex) Box(min, max Point) iter.Seq2[Point, uint64] {
+	return func(yield func(Point, uint64) bool) {
+		zmin, zmax, ok := s.box(min, max)
+		if !ok {
+			return
+		}
+		key, val, ok := s.array.First(zmin)
+		for ok && key <= zmax {
+			if !s.inBox(key, zmin, zmax) {
+				key, val, ok = s.array.First(s.bigmin(key, zmin, zmax))
+				continue
+			}
+			if !yield(s.decode(key), val) || key == zmax {
+				return
+			}
+			key, val, ok = s.array.Next(key)
+		}
+	}
+}
+
+// Count the number of points inside the box from min to max (inclusive on every axis) by walking them as Box does.
+func (s *SpatialIndex) Count(min, max Point) uint64 {
+	var n uint64
+	for range s.Box(min, max) {
+		n++
+	}
+	return n
+}
+
+// ApproxCount estimates the number of points inside the box from min to max (inclusive on every axis) without
+// visiting them. The box is split in halves along its Morton key range a few times (the lower half ends at the
+// LITMAX of the split and the upper half starts at its BIGMIN), and the points in the key range of each part are
+// counted with CountFrom. The estimate is never less than the exact Count; it includes points outside the box
+// whose keys fall inside the key range of a part.
+func (s *SpatialIndex) ApproxCount(min, max Point) uint64 {
+	zmin, zmax, ok := s.box(min, max)
+	if !ok {
+		return 0
+	}
+	return s.approxCount(zmin, zmax, approxCountDepth)
+}
+
+func (s *SpatialIndex) approxCount(zmin, zmax uint64, depth int) uint64 {
+	ct := s.array.CountFrom(zmin, zmax)
+	if depth == 0 || ct == 0 || zmin == zmax {
+		return ct
+	}
+	// split on the highest bit where the corners differ, which is 0 in zmin and 1 in zmax
+	bit := 63 - bits.LeadingZeros64(zmin^zmax)
+	litmax, bigmin := s.load0111(zmax, bit), s.load1000(zmin, bit)
+	return s.approxCount(zmin, litmax, depth-1) + s.approxCount(bigmin, zmax, depth-1)
+}
+
+// box returns the Morton keys of the corners of the box from min to max, and false if the box is empty. The box is
+// cut to the coordinates the index can hold, so a query may reach past them.
+func (s *SpatialIndex) box(min, max Point) (uint64, uint64, bool) {
+	if s.threeD {
+		max = Point{X: clamp21(max.X), Y: clamp21(max.Y), Z: clamp21(max.Z)}
+	}
+	if min.X > max.X || min.Y > max.Y || s.threeD && min.Z > max.Z {
+		return 0, 0, false
+	}
+	return s.encode(min), s.encode(max), true
+}
+
+// clamp21 returns v, or the largest 21-bit coordinate if v is larger.
+func clamp21(v uint32) uint32 {
+	return min(v, 1<<21-1)
+}
+
+// fits tests if the coordinates of p can be held by the index.
+func (s *SpatialIndex) fits(p Point) bool {
+	return !s.threeD || p.X|p.Y|p.Z < 1<<21
+}
+
+// inBox tests if key is inside the box with corner keys zmin and zmax. Masking a key to the bits of one dimension
+// preserves the order of that coordinate, so no decoding is needed.
+func (s *SpatialIndex) inBox(key, zmin, zmax uint64) bool {
+	for d := 0; d < s.dims(); d++ {
+		m := s.dimMask(d)
+		if k := key & m; k < zmin&m || k > zmax&m {
+			return false
+		}
+	}
+	return true
+}
+
+// bigmin returns the smallest key greater than key that is inside the box with corner keys zmin and zmax, where
+// key is between zmin and zmax but outside the box (Tropf and Herzog, 1981). There always is one, since zmax is
+// inside the box.
+func (s *SpatialIndex) bigmin(key, zmin, zmax uint64) uint64 {
+	bigmin := zmax
+	for bit := s.dims()*s.coordBits() - 1; bit >= 0; bit-- {
+		mask := uint64(1) << bit
+		switch kb, minb, maxb := key&mask != 0, zmin&mask != 0, zmax&mask != 0; {
+		case !kb && !minb && maxb:
+			bigmin = s.load1000(zmin, bit)
+			zmax = s.load0111(zmax, bit)
+		case !kb && minb && maxb:
+			return zmin
+		case kb && !minb && !maxb:
+			return bigmin
+		case kb && !minb && maxb:
+			zmin = s.load1000(zmin, bit)
+		}
+	}
+	return bigmin
+}
+
+// load1000 sets bit in key and clears the lower bits of the same dimension.
+func (s *SpatialIndex) load1000(key uint64, bit int) uint64 {
+	mask := uint64(1) << bit
+	return key&^(s.dimMask(bit%s.dims())&(mask-1)) | mask
+}
+
+// load0111 clears bit in key and sets the lower bits of the same dimension.
+func (s *SpatialIndex) load0111(key uint64, bit int) uint64 {
+	mask := uint64(1) << bit
+	return key&^mask | s.dimMask(bit%s.dims())&(mask-1)
+}
+
+// dimMask returns the bits of the Morton key that hold dimension d.
+func (s *SpatialIndex) dimMask(d int) uint64 {
+	if !s.threeD {
+		return 0x5555555555555555 << d
+	}
+	return 0x1249249249249249 << d
+}
+
+func (s *SpatialIndex) coordBits() int {
+	if !s.threeD {
+		return 32
+	}
+	return 21
+}
+
+func (s *SpatialIndex) encode(p Point) uint64 {
+	if !s.threeD {
+		return spread2(p.X) | spread2(p.Y)<<1
+	}
+	if p.X|p.Y|p.Z >= 1<<21 {
+		panic("judy: 3D SpatialIndex coordinate does not fit in 21 bits")
+	}
+	return spread3(p.X) | spread3(p.Y)<<1 | spread3(p.Z)<<2
+}
+
+func (s *SpatialIndex) decode(key uint64) Point {
+	if !s.threeD {
+		return Point{X: compact2(key), Y: compact2(key >> 1)}
+	}
+	return Point{X: compact3(key), Y: compact3(key >> 1), Z: compact3(key >> 2)}
+}
+
+// spread2 spaces the bits of v out to every other bit.
+func spread2(v uint32) uint64 {
+	x := uint64(v)
+	x = (x | x<<16) & 0x0000ffff0000ffff
+	x = (x | x<<8) & 0x00ff00ff00ff00ff
+	x = (x | x<<4) & 0x0f0f0f0f0f0f0f0f
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+	return x
+}
+
+// compact2 is the inverse of spread2.
+func compact2(x uint64) uint32 {
+	x &= 0x5555555555555555
+	x = (x | x>>1) & 0x3333333333333333
+	x = (x | x>>2) & 0x0f0f0f0f0f0f0f0f
+	x = (x | x>>4) & 0x00ff00ff00ff00ff
+	x = (x | x>>8) & 0x0000ffff0000ffff
+	x = (x | x>>16) & 0x00000000ffffffff
+	return uint32(x)
+}
+
+// spread3 spaces the 21 bits of v out to every third bit.
+func spread3(v uint32) uint64 {
+	x := uint64(v) & 0x1fffff
+	x = (x | x<<32) & 0x001f00000000ffff
+	x = (x | x<<16) & 0x001f0000ff0000ff
+	x = (x | x<<8) & 0x100f00f00f00f00f
+	x = (x | x<<4) & 0x10c30c30c30c30c3
+	x = (x | x<<2) & 0x1249249249249249
+	return x
+}
+
+// compact3 is the inverse of spread3.
+func compact3(x uint64) uint32 {
+	x &= 0x1249249249249249
+	x = (x | x>>2) & 0x10c30c30c30c30c3
+	x = (x | x>>4) & 0x100f00f00f00f00f
+	x = (x | x>>8) & 0x001f0000ff0000ff
+	x = (x | x>>16) & 0x001f00000000ffff
+	x = (x | x>>32) & 0x00000000001fffff
+	return uint32(x)
+}
+
+// Return the number of bytes of memory currently in use by the index.
+func (s *SpatialIndex) MemoryUsed() uint64 {
+	return s.array.MemoryUsed()
+}
+
+// Free the entire index.
+// Return the number of bytes freed.
+func (s *SpatialIndex) Free() uint64 {
+	return s.array.Free()
+}
@@ -0,0 +1,208 @@
+package judy
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSpatialIndexEncode(t *testing.T) {
+
+	s2, s3 := NewSpatialIndex(2), NewSpatialIndex(3)
+
+	if k := s2.encode(Point{X: 0b11, Y: 0b01}); k != 0b0111 {
+		t.Errorf("2D key of (3, 1) should be 0b0111, was %b", k)
+	}
+	if k := s3.encode(Point{X: 1, Y: 1, Z: 1}); k != 0b111 {
+		t.Errorf("3D key of (1, 1, 1) should be 0b111, was %b", k)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := Point{X: rng.Uint32(), Y: rng.Uint32()}
+		if q := s2.decode(s2.encode(p)); q != p {
+			t.Fatalf("2D %v should round-trip, was %v", p, q)
+		}
+		p = Point{X: rng.Uint32() >> 11, Y: rng.Uint32() >> 11, Z: rng.Uint32() >> 11}
+		if q := s3.decode(s3.encode(p)); q != p {
+			t.Fatalf("3D %v should round-trip, was %v", p, q)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Inserting a 3D coordinate over 21 bits should panic")
+		}
+	}()
+	s3.Insert(Point{X: 1 << 21}, 1)
+}
+
+func TestSpatialIndexZeroValue(t *testing.T) {
+
+	// the default value is a 2D index
+	s := SpatialIndex{}
+	defer s.Free()
+
+	for x := uint32(0); x < 10; x++ {
+		for y := uint32(0); y < 10; y++ {
+			s.Insert(Point{X: x, Y: y}, uint64(x*10+y))
+		}
+	}
+	if k := s.encode(Point{X: 0b11, Y: 0b01}); k != 0b0111 {
+		t.Errorf("2D key of (3, 1) should be 0b0111, was %b", k)
+	}
+	if ct := s.Count(Point{X: 2, Y: 3}, Point{X: 4, Y: 8}); ct != 18 {
+		t.Errorf("Count should be 18, was %v", ct)
+	}
+	if ct := s.ApproxCount(Point{X: 2, Y: 3}, Point{X: 4, Y: 8}); ct < 18 {
+		t.Errorf("ApproxCount should be at least 18, was %v", ct)
+	}
+}
+
+func TestNewSpatialIndexPanics(t *testing.T) {
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewSpatialIndex(4) should panic")
+		}
+	}()
+	NewSpatialIndex(4)
+}
+
+func TestSpatialIndexInsertGet(t *testing.T) {
+
+	s := NewSpatialIndex(2)
+	defer s.Free()
+
+	s.Insert(Point{X: 1, Y: 2}, 12)
+	s.Insert(Point{X: math.MaxUint32, Y: math.MaxUint32}, 99)
+	s.Insert(Point{X: 1, Y: 2}, 21)
+
+	if v, ok := s.Get(Point{X: 1, Y: 2}); !ok || v != 21 {
+		t.Errorf("Get(1, 2) should be 21, was %v,%v", v, ok)
+	}
+	if ct := s.CountAll(); ct != 2 {
+		t.Errorf("CountAll should be 2, was %v", ct)
+	}
+
+	max := Point{X: math.MaxUint32, Y: math.MaxUint32}
+	n := 0
+	for p, v := range s.Box(Point{X: 1, Y: 1}, max) {
+		if p != max && p != (Point{X: 1, Y: 2}) || v == 0 {
+			t.Errorf("Box returned unexpected point %v=%v", p, v)
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("Box up to MaxUint32 should return 2 points, returned %v", n)
+	}
+
+	if !s.Delete(Point{X: 1, Y: 2}) || s.Delete(Point{X: 1, Y: 2}) {
+		t.Error("Delete(1, 2) should succeed once")
+	}
+	if ct := s.Count(Point{}, max); ct != 1 {
+		t.Errorf("Count should be 1 after Delete, was %v", ct)
+	}
+	if ct := s.Count(Point{X: 5}, Point{X: 4, Y: 10}); ct != 0 {
+		t.Errorf("Count of an empty box should be 0, was %v", ct)
+	}
+}
+
+func testSpatialIndexBoxes(t *testing.T, dims int, size uint32) {
+
+	rng := rand.New(rand.NewSource(int64(dims)))
+	s := NewSpatialIndex(dims)
+	defer s.Free()
+
+	points := map[Point]uint64{}
+	for i := 0; i < 3000; i++ {
+		p := Point{X: uint32(rng.Intn(int(size))), Y: uint32(rng.Intn(int(size)))}
+		if dims == 3 {
+			p.Z = uint32(rng.Intn(int(size)))
+		}
+		points[p] = uint64(i)
+		s.Insert(p, uint64(i))
+	}
+
+	inBox := func(p, min, max Point) bool {
+		return p.X >= min.X && p.X <= max.X && p.Y >= min.Y && p.Y <= max.Y && (dims == 2 || p.Z >= min.Z && p.Z <= max.Z)
+	}
+
+	for i := 0; i < 200; i++ {
+		var min, max Point
+		min.X, max.X = uint32(rng.Intn(int(size))), uint32(rng.Intn(int(size)))
+		min.Y, max.Y = uint32(rng.Intn(int(size))), uint32(rng.Intn(int(size)))
+		if dims == 3 {
+			min.Z, max.Z = uint32(rng.Intn(int(size))), uint32(rng.Intn(int(size)))
+		}
+		min.X, max.X = minmax(min.X, max.X)
+		min.Y, max.Y = minmax(min.Y, max.Y)
+		min.Z, max.Z = minmax(min.Z, max.Z)
+
+		var expected uint64
+		for p := range points {
+			if inBox(p, min, max) {
+				expected++
+			}
+		}
+
+		var got uint64
+		var prev uint64
+		for p, v := range s.Box(min, max) {
+			if !inBox(p, min, max) || points[p] != v {
+				t.Fatalf("%vD Box(%v, %v) returned %v=%v", dims, min, max, p, v)
+			}
+			if k := s.encode(p); got > 0 && k <= prev {
+				t.Fatalf("%vD Box should return points in Morton key order", dims)
+			} else {
+				prev = k
+			}
+			got++
+		}
+		if got != expected {
+			t.Errorf("%vD Box(%v, %v) should return %v points, returned %v", dims, min, max, expected, got)
+		}
+		if approx := s.ApproxCount(min, max); approx < expected || approx > s.array.CountFrom(s.encode(min), s.encode(max)) {
+			t.Errorf("%vD ApproxCount(%v, %v) should be between %v and the key range count, was %v", dims, min, max, expected, approx)
+		}
+	}
+}
+
+func minmax(a, b uint32) (uint32, uint32) {
+	if a > b {
+		return b, a
+	}
+	return a, b
+}
+
+func TestSpatialIndex3DQueryBounds(t *testing.T) {
+
+	s := NewSpatialIndex(3)
+	defer s.Free()
+
+	top := uint32(1<<21 - 1)
+	s.Insert(Point{X: 1, Y: 2, Z: 3}, 1)
+	s.Insert(Point{X: top, Y: top, Z: top}, 2)
+
+	all := Point{X: math.MaxUint32, Y: math.MaxUint32, Z: math.MaxUint32}
+	if ct := s.Count(Point{}, all); ct != 2 {
+		t.Errorf("Count over every coordinate should be 2, was %v", ct)
+	}
+	if ct := s.ApproxCount(Point{}, all); ct != 2 {
+		t.Errorf("ApproxCount over every coordinate should be 2, was %v", ct)
+	}
+	if ct := s.Count(Point{X: top, Y: 1 << 21, Z: 0}, all); ct != 0 {
+		t.Errorf("A box starting past the coordinates should be empty, Count was %v", ct)
+	}
+	if _, ok := s.Get(all); ok || s.Delete(all) {
+		t.Error("A point past the coordinates should not be found")
+	}
+}
+
+func TestSpatialIndexBoxes2D(t *testing.T) {
+	testSpatialIndexBoxes(t, 2, 300)
+}
+
+func TestSpatialIndexBoxes3D(t *testing.T) {
+	testSpatialIndexBoxes(t, 3, 40)
+}
@@ -0,0 +1,131 @@
+package judy
+
+// Return a new Judy1 array holding the indexes of the Judy1 array for which keep returns true.
+// The caller owns the new array and must Free it. If keep panics, the partially built array is freed before the
+// panic continues.
+func (j *Judy1) Filter(keep func(index uint64) bool) *Judy1 {
+	return build1(func(dst *Judy1) {
+		for idx, ok := j.First(0); ok; idx, ok = j.Next(idx) {
+			if keep(idx) {
+				dst.Set(idx)
+			}
+		}
+	})
+}
+
+// Return a new Judy1 array holding f(index) for every index of the Judy1 array. Indexes that map to the same
+// index are collapsed into one. The caller owns the new array and must Free it. If f panics, the partially built
+// array is freed before the panic continues.
+func (j *Judy1) MapKeys(f func(index uint64) uint64) *Judy1 {
+	return build1(func(dst *Judy1) {
+		for idx, ok := j.First(0); ok; idx, ok = j.Next(idx) {
+			dst.Set(f(idx))
+		}
+	})
+}
+
+// Return a new JudyL array that maps every index of the Judy1 array to f(index).
+// The caller owns the new array and must Free it. If f panics, the partially built array is freed before the
+// panic continues.
+func (j *Judy1) MapValues(f func(index uint64) uint64) *JudyL {
+	return buildL(func(dst *JudyL) {
+		for idx, ok := j.First(0); ok; idx, ok = j.Next(idx) {
+			dst.Insert(idx, f(idx))
+		}
+	})
+}
+
+// Return a new JudyL array holding the index/value pairs of the JudyL array for which keep returns true.
+// The caller owns the new array and must Free it. If keep panics, the partially built array is freed before the
+// panic continues.
+func (j *JudyL) Filter(keep func(index, value uint64) bool) *JudyL {
+	return buildL(func(dst *JudyL) {
+		for idx, val, ok := j.First(0); ok; idx, val, ok = j.Next(idx) {
+			if keep(idx, val) {
+				dst.Insert(idx, val)
+			}
+		}
+	})
+}
+
+// Return a new JudyL array with the same indexes as the JudyL array, where each value is replaced by
+// f(index, value). The caller owns the new array and must Free it. If f panics, the partially built array is freed
+// before the panic continues.
+func (j *JudyL) MapValues(f func(index, value uint64) uint64) *JudyL {
+	return buildL(func(dst *JudyL) {
+		for idx, val, ok := j.First(0); ok; idx, val, ok = j.Next(idx) {
+			dst.Insert(idx, f(idx, val))
+		}
+	})
+}
+
+// Return a new JudyL array where each index/value pair of the JudyL array is stored at index f(index, value).
+// When several pairs map to the same index, their values are combined with resolve in ascending order of the
+// original index (see MergeInto). A nil resolve keeps the last value, like MergeKeepLast.
+// The caller owns the new array and must Free it. If f or resolve panics, the partially built array is freed
+// before the panic continues.
+func (j *JudyL) MapKeys(f func(index, value uint64) uint64, resolve Resolver) *JudyL {
+	return buildL(func(dst *JudyL) {
+		for idx, val, ok := j.First(0); ok; idx, val, ok = j.Next(idx) {
+			key := f(idx, val)
+			if prev, found := dst.Get(key); found && resolve != nil {
+				val = resolve(key, prev, val)
+			}
+			dst.Insert(key, val)
+		}
+	})
+}
+
+// Fold1 reduces the Judy1 array to a single value by calling f for every index in ascending order, starting with
+// the accumulator init.
+//
+//    // sum of the indexes present
+//    sum := judy.Fold1(&j, 0, func(acc, index uint64) uint64 { return acc + index })
+func Fold1[A any](j *Judy1, init A, f func(acc A, index uint64) A) A {
+	acc := init
+	for idx, ok := j.First(0); ok; idx, ok = j.Next(idx) {
+		acc = f(acc, idx)
+	}
+	return acc
+}
+
+// FoldL reduces the JudyL array to a single value by calling f for every index/value pair in ascending index
+// order, starting with the accumulator init.
+//
+//    // largest value present
+//    top := judy.FoldL(&j, 0, func(acc, index, value uint64) uint64 { return max(acc, value) })
+func FoldL[A any](j *JudyL, init A, f func(acc A, index, value uint64) A) A {
+	acc := init
+	for idx, val, ok := j.First(0); ok; idx, val, ok = j.Next(idx) {
+		acc = f(acc, idx, val)
+	}
+	return acc
+}
+
+// build1 allocates a new Judy1 array and fills it, freeing it again if fill panics.
+func build1(fill func(dst *Judy1)) *Judy1 {
+	dst := &Judy1{}
+	done := false
+	defer func() {
+		if !done {
+			dst.Free()
+		}
+	}()
+	fill(dst)
+	done = true
+	return dst
+}
+
+// buildL allocates a new JudyL array and fills it, freeing it again if fill panics.
+func buildL(fill func(dst *JudyL)) *JudyL {
+	dst := &JudyL{}
+	done := false
+	defer func() {
+		if !done {
+			dst.Free()
+		}
+	}()
+	fill(dst)
+	done = true
+	return dst
+}
@@ -0,0 +1,221 @@
+package judy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJudy1Filter(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	var i uint64
+	for i = 0; i < 100; i++ {
+		j.Set(i)
+	}
+
+	even := j.Filter(func(index uint64) bool { return index%2 == 0 })
+	defer even.Free()
+
+	if ct := even.CountAll(); ct != 50 {
+		t.Errorf("Count should be 50, was %v", ct)
+	}
+	if even.Test(3) || !even.Test(98) {
+		t.Error("Filter should keep only even indexes")
+	}
+	if ct := j.CountAll(); ct != 100 {
+		t.Errorf("Source array should not be modified, count was %v", ct)
+	}
+}
+
+func TestJudy1MapKeys(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	var i uint64
+	for i = 0; i < 100; i++ {
+		j.Set(i)
+	}
+
+	tens := j.MapKeys(func(index uint64) uint64 { return index / 10 })
+	defer tens.Free()
+
+	if ct := tens.CountAll(); ct != 10 {
+		t.Errorf("Colliding keys should collapse to 10 indexes, count was %v", ct)
+	}
+}
+
+func TestJudy1MapValues(t *testing.T) {
+
+	j := Judy1{}
+	defer j.Free()
+
+	j.Set(3)
+	j.Set(math.MaxUint64)
+
+	l := j.MapValues(func(index uint64) uint64 { return index * 2 })
+	defer l.Free()
+
+	if v, ok := l.Get(3); !ok || v != 6 {
+		t.Errorf("Value of 3 should be 6, was %v,%v", v, ok)
+	}
+	if v, ok := l.Get(math.MaxUint64); !ok || v != math.MaxUint64-1 {
+		t.Errorf("Value of MaxUint64 should be MaxUint64-1, was %v,%v", v, ok)
+	}
+}
+
+func TestJudyLFilter(t *testing.T) {
+
+	j := JudyL{}
+	defer j.Free()
+
+	var i uint64
+	for i = 0; i < 100; i++ {
+		j.Insert(i, i*10)
+	}
+
+	big := j.Filter(func(index, value uint64) bool { return value > 900 })
+	defer big.Free()
+
+	if ct := big.CountAll(); ct != 9 {
+		t.Errorf("Count should be 9, was %v", ct)
+	}
+	if idx, val, ok := big.First(0); !ok || idx != 91 || val != 910 {
+		t.Errorf("First should be 91,910 was %v,%v,%v", idx, val, ok)
+	}
+}
+
+func TestJudyLMapValues(t *testing.T) {
+
+	j := JudyL{}
+	defer j.Free()
+
+	var i uint64
+	for i = 0; i < 100; i++ {
+		j.Insert(i, i)
+	}
+
+	sq := j.MapValues(func(index, value uint64) uint64 { return value * value })
+	defer sq.Free()
+
+	if ct := sq.CountAll(); ct != 100 {
+		t.Errorf("Count should be 100, was %v", ct)
+	}
+	if v, _ := sq.Get(12); v != 144 {
+		t.Errorf("Value of 12 should be 144, was %v", v)
+	}
+}
+
+func TestJudyLMapKeys(t *testing.T) {
+
+	j := JudyL{}
+	defer j.Free()
+
+	var i uint64
+	for i = 0; i < 100; i++ {
+		j.Insert(i, i)
+	}
+
+	sums := j.MapKeys(func(index, value uint64) uint64 { return index / 10 }, MergeSum)
+	defer sums.Free()
+	lasts := j.MapKeys(func(index, value uint64) uint64 { return index / 10 }, nil)
+	defer lasts.Free()
+
+	if ct := sums.CountAll(); ct != 10 {
+		t.Errorf("Count should be 10, was %v", ct)
+	}
+	if v, _ := sums.Get(2); v != 245 {
+		t.Errorf("Sum for key 2 should be 245, was %v", v)
+	}
+	if v, _ := lasts.Get(2); v != 29 {
+		t.Errorf("Last value for key 2 should be 29, was %v", v)
+	}
+}
+
+func TestFold(t *testing.T) {
+
+	j1 := Judy1{}
+	defer j1.Free()
+	jl := JudyL{}
+	defer jl.Free()
+
+	if n := Fold1(&j1, 7, func(acc int, index uint64) int { return acc + 1 }); n != 7 {
+		t.Errorf("Fold of an empty array should return init, was %v", n)
+	}
+
+	var i uint64
+	for i = 1; i <= 10; i++ {
+		j1.Set(i)
+		jl.Insert(i, i*i)
+	}
+
+	if sum := Fold1(&j1, 0, func(acc, index uint64) uint64 { return acc + index }); sum != 55 {
+		t.Errorf("Sum of indexes should be 55, was %v", sum)
+	}
+	keys := FoldL(&jl, []uint64(nil), func(acc []uint64, index, value uint64) []uint64 {
+		if value > 50 {
+			acc = append(acc, index)
+		}
+		return acc
+	})
+	if len(keys) != 3 || keys[0] != 8 || keys[2] != 10 {
+		t.Errorf("Fold should collect [8 9 10], was %v", keys)
+	}
+}
+
+func TestBuildFreesOnPanic(t *testing.T) {
+
+	var j1 *Judy1
+	var jl *JudyL
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("The panic should be propagated")
+			}
+		}()
+		build1(func(dst *Judy1) {
+			j1 = dst
+			dst.Set(1)
+			panic("fill failed")
+		})
+	}()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("The panic should be propagated")
+			}
+		}()
+		buildL(func(dst *JudyL) {
+			jl = dst
+			dst.Insert(1, 1)
+			panic("fill failed")
+		})
+	}()
+
+	if j1.array != nil || jl.array != nil {
+		t.Error("Partially built arrays should be freed on panic")
+	}
+}
+
+func TestJudyLFilterPanic(t *testing.T) {
+
+	j := JudyL{}
+	defer j.Free()
+	j.Insert(1, 1)
+	j.Insert(2, 2)
+
+	defer func() {
+		if r := recover(); r != "bad value" {
+			t.Errorf("The panic should be propagated, recovered %v", r)
+		}
+	}()
+	j.Filter(func(index, value uint64) bool {
+		if value == 2 {
+			panic("bad value")
+		}
+		return true
+	})
+}
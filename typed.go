@@ -0,0 +1,173 @@
+package judy
+
+import (
+	"math"
+	"time"
+)
+
+// A Codec converts values of type V to and from the uint64 word stored in a JudyL array.
+// Decode(Encode(v)) must return v for every value the codec accepts.
+type Codec[V any] interface {
+	Encode(v V) uint64
+	Decode(w uint64) V
+}
+
+// Int64Codec stores an int64 as its two's complement bit pattern.
+type Int64Codec struct{}
+
+func (Int64Codec) Encode(v int64) uint64 { return uint64(v) }
+func (Int64Codec) Decode(w uint64) int64 { return int64(w) }
+
+// Float64Codec stores a float64 as its IEEE 754 bit pattern (see math.Float64bits), so every value including
+// negative zero, infinities and NaN payloads round-trips exactly.
+type Float64Codec struct{}
+
+func (Float64Codec) Encode(v float64) uint64 { return math.Float64bits(v) }
+func (Float64Codec) Decode(w uint64) float64 { return math.Float64frombits(w) }
+
+// BoolCodec stores false as 0 and true as 1. Any non-zero word decodes as true.
+type BoolCodec struct{}
+
+func (BoolCodec) Encode(v bool) uint64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+func (BoolCodec) Decode(w uint64) bool { return w != 0 }
+
+// DurationCodec stores a time.Duration as its number of nanoseconds.
+type DurationCodec struct{}
+
+func (DurationCodec) Encode(v time.Duration) uint64 { return uint64(v) }
+func (DurationCodec) Decode(w uint64) time.Duration { return time.Duration(w) }
+
+// TimeCodec stores a time.Time as nanoseconds since the Unix epoch (see time.Time.UnixNano), which covers the
+// years 1678 to 2262. The instant round-trips exactly, so decoded times are Equal to the encoded ones, but the
+// location and monotonic clock reading are not stored: times decode in UTC.
+// Encode panics if the time is outside the representable range, rather than silently storing a different instant.
+type TimeCodec struct{}
+
+var (
+	minUnixNanoTime = time.Unix(0, math.MinInt64)
+	maxUnixNanoTime = time.Unix(0, math.MaxInt64)
+)
+
+func (TimeCodec) Encode(v time.Time) uint64 {
+	if v.Before(minUnixNanoTime) || v.After(maxUnixNanoTime) {
+		panic("judy: time out of range for TimeCodec: " + v.String())
+	}
+	return uint64(v.UnixNano())
+}
+func (TimeCodec) Decode(w uint64) time.Time { return time.Unix(0, int64(w)).UTC() }
+
+// A TypedL is a JudyL array whose values are of type V, converted to and from the stored uint64 word with the Codec C.
+// Indexes are plain uint64 values, exactly as in JudyL.
+// The default value of this struct is a valid empty array.
+//
+//    m := TypedL[float64, Float64Codec]{}
+//    defer m.Free()
+//
+//    m.Insert(11235, 0.5)
+//    val, ok := m.Get(11235) // val == 0.5, ok == true
+//
+// NOTE: The underlying JudyL array allocates memory outside of the Go runtime. It is very important that you call
+// Free() on a TypedL after using it to prevent memory leaks.
+type TypedL[V any, C Codec[V]] struct {
+	array JudyL
+	codec C
+}
+
+// NewTypedL returns an empty TypedL that stores its values with codec. It is only needed for a codec with state;
+// for the codecs in this package the default value of TypedL is ready to use.
+func NewTypedL[V any, C Codec[V]](codec C) *TypedL[V, C] {
+	return &TypedL[V, C]{codec: codec}
+}
+
+// Insert an Index and Value into the TypedL array. If the Index was already present, the current Value is replaced
+// with the provided Value.
+func (m *TypedL[V, C]) Insert(index uint64, value V) {
+	m.array.Insert(index, m.codec.Encode(value))
+}
+
+// Delete the Index/Value pair from the TypedL array.
+// Returns true if successful. Returns false if Index was not present.
+func (m *TypedL[V, C]) Delete(index uint64) bool {
+	return m.array.Delete(index)
+}
+
+// Get the Value associated with Index in the TypedL array
+//   returns (value, true) if the index was found
+//   returns (_, false) if the index was not found
+func (m *TypedL[V, C]) Get(index uint64) (V, bool) {
+	return m.decode(m.array.Get(index))
+}
+
+// Count the number of indexes present in the TypedL array.
+func (m *TypedL[V, C]) CountAll() uint64 {
+	return m.array.CountAll()
+}
+
+// Count the number of indexes present in the TypedL array between indexA and indexB (inclusive).
+func (m *TypedL[V, C]) CountFrom(indexA, indexB uint64) uint64 {
+	return m.array.CountFrom(indexA, indexB)
+}
+
+// Search (inclusive) for the first index present that is equal to or greater than the passed index.
+// See JudyL.First.
+func (m *TypedL[V, C]) First(index uint64) (uint64, V, bool) {
+	return m.decodePair(m.array.First(index))
+}
+
+// Search (exclusive) for the first index present that is greater than the passed index.
+// See JudyL.Next.
+func (m *TypedL[V, C]) Next(index uint64) (uint64, V, bool) {
+	return m.decodePair(m.array.Next(index))
+}
+
+// Search (inclusive) for the last index present that is equal to or less than the passed index.
+// See JudyL.Last.
+func (m *TypedL[V, C]) Last(index uint64) (uint64, V, bool) {
+	return m.decodePair(m.array.Last(index))
+}
+
+// Search (exclusive) for the last index present that is less than the passed index.
+// See JudyL.Prev.
+func (m *TypedL[V, C]) Prev(index uint64) (uint64, V, bool) {
+	return m.decodePair(m.array.Prev(index))
+}
+
+// Locate the Nth index that is present in the TypedL array (Nth = 1 returns the first index present).
+// See JudyL.ByCount.
+func (m *TypedL[V, C]) ByCount(nth uint64) (uint64, V, bool) {
+	return m.decodePair(m.array.ByCount(nth))
+}
+
+// Array returns the underlying JudyL array holding the encoded values. It is freed with the TypedL.
+func (m *TypedL[V, C]) Array() *JudyL {
+	return &m.array
+}
+
+// Return the number of bytes of memory currently in use by the TypedL array.
+func (m *TypedL[V, C]) MemoryUsed() uint64 {
+	return m.array.MemoryUsed()
+}
+
+// Free the entire TypedL array.
+// Return the number of bytes freed.
+func (m *TypedL[V, C]) Free() uint64 {
+	return m.array.Free()
+}
+
+func (m *TypedL[V, C]) decode(w uint64, ok bool) (V, bool) {
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return m.codec.Decode(w), true
+}
+
+func (m *TypedL[V, C]) decodePair(index, w uint64, ok bool) (uint64, V, bool) {
+	v, ok := m.decode(w, ok)
+	return index, v, ok
+}
@@ -0,0 +1,147 @@
+package judy
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestInt64Codec(t *testing.T) {
+
+	c := Int64Codec{}
+	for _, v := range []int64{0, 1, -1, math.MinInt64, math.MaxInt64} {
+		if r := c.Decode(c.Encode(v)); r != v {
+			t.Errorf("%v should round-trip, was %v", v, r)
+		}
+	}
+}
+
+func TestFloat64Codec(t *testing.T) {
+
+	c := Float64Codec{}
+	for _, v := range []float64{0, math.Copysign(0, -1), 1.5, -1e300, math.SmallestNonzeroFloat64, math.Inf(1), math.Inf(-1), math.NaN()} {
+		if r := c.Decode(c.Encode(v)); math.Float64bits(r) != math.Float64bits(v) {
+			t.Errorf("%v should round-trip bit for bit, was %v", v, r)
+		}
+	}
+}
+
+func TestBoolCodec(t *testing.T) {
+
+	c := BoolCodec{}
+	if c.Decode(c.Encode(true)) != true || c.Decode(c.Encode(false)) != false {
+		t.Error("Booleans should round-trip")
+	}
+	if c.Encode(false) != 0 || c.Encode(true) != 1 {
+		t.Error("Booleans should encode as 0 and 1")
+	}
+}
+
+func TestDurationCodec(t *testing.T) {
+
+	c := DurationCodec{}
+	for _, v := range []time.Duration{0, time.Nanosecond, -time.Hour, math.MinInt64, math.MaxInt64} {
+		if r := c.Decode(c.Encode(v)); r != v {
+			t.Errorf("%v should round-trip, was %v", v, r)
+		}
+	}
+}
+
+func TestTimeCodec(t *testing.T) {
+
+	c := TimeCodec{}
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	for _, v := range []time.Time{
+		time.Unix(0, 0),
+		time.Date(2013, 11, 26, 12, 30, 0, 123456789, loc),
+		time.Date(1900, 1, 1, 0, 0, 0, 1, time.UTC),
+		time.Now(),
+		time.Unix(0, math.MinInt64),
+		time.Unix(0, math.MaxInt64),
+	} {
+		r := c.Decode(c.Encode(v))
+		if !r.Equal(v) {
+			t.Errorf("%v should round-trip, was %v", v, r)
+		}
+		if r.Location() != time.UTC {
+			t.Errorf("%v should decode in UTC, was %v", v, r.Location())
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Encoding a time out of range should panic")
+		}
+	}()
+	c.Encode(time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestTypedL(t *testing.T) {
+
+	m := NewTypedL[float64](Float64Codec{})
+	defer m.Free()
+
+	if _, ok := m.Get(1); ok {
+		t.Error("Get on an empty array should not be found")
+	}
+
+	var i uint64
+	for i = 0; i < 100; i++ {
+		m.Insert(i, float64(i)-49.5)
+	}
+
+	if ct := m.CountAll(); ct != 100 {
+		t.Errorf("Count should be 100, was %v", ct)
+	}
+	if ct := m.CountFrom(10, 19); ct != 10 {
+		t.Errorf("Count should be 10, was %v", ct)
+	}
+	if v, ok := m.Get(0); !ok || v != -49.5 {
+		t.Errorf("Get(0) should be -49.5,true was %v,%v", v, ok)
+	}
+	if idx, v, ok := m.First(50); !ok || idx != 50 || v != 0.5 {
+		t.Errorf("First(50) should be 50,0.5,true was %v,%v,%v", idx, v, ok)
+	}
+	if idx, v, ok := m.Next(50); !ok || idx != 51 || v != 1.5 {
+		t.Errorf("Next(50) should be 51,1.5,true was %v,%v,%v", idx, v, ok)
+	}
+	if idx, v, ok := m.Last(math.MaxUint64); !ok || idx != 99 || v != 49.5 {
+		t.Errorf("Last(MaxUint64) should be 99,49.5,true was %v,%v,%v", idx, v, ok)
+	}
+	if idx, v, ok := m.Prev(1); !ok || idx != 0 || v != -49.5 {
+		t.Errorf("Prev(1) should be 0,-49.5,true was %v,%v,%v", idx, v, ok)
+	}
+	if idx, v, ok := m.ByCount(3); !ok || idx != 2 || v != -47.5 {
+		t.Errorf("ByCount(3) should be 2,-47.5,true was %v,%v,%v", idx, v, ok)
+	}
+	if _, _, ok := m.Next(99); ok {
+		t.Error("Next(99) should not be found")
+	}
+	if w, _ := m.Array().Get(0); w != math.Float64bits(-49.5) {
+		t.Errorf("The underlying array should hold the encoded value, was %x", w)
+	}
+
+	if !m.Delete(0) || m.Delete(0) {
+		t.Error("Delete(0) should succeed once")
+	}
+	if mem := m.MemoryUsed(); mem == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+}
+
+func TestTypedLTime(t *testing.T) {
+
+	// the default value is ready to use
+	m := TypedL[time.Time, TimeCodec]{}
+	defer m.Free()
+
+	now := time.Now()
+	m.Insert(7, now)
+
+	if v, ok := m.Get(7); !ok || !v.Equal(now) {
+		t.Errorf("Get(7) should be %v, was %v,%v", now, v, ok)
+	}
+	if v, ok := m.Get(8); ok || !v.IsZero() {
+		t.Errorf("Get(8) should be the zero time and false, was %v,%v", v, ok)
+	}
+}
@@ -0,0 +1,127 @@
+package judy
+
+import "time"
+
+// A WindowSet remembers the uint64 IDs seen over a sliding time window, such as event IDs to deduplicate over the
+// last few minutes. Time is divided into generations of window/generations each, and the IDs first seen in each
+// generation are kept in their own Judy1 array. As time moves on, the generations that have left the window are
+// expired by freeing their arrays, so expiry costs one Free per generation rather than one operation per ID.
+//
+//    w := judy.NewWindowSet(10*time.Minute, 10)
+//    defer w.Free()
+//
+//    if w.Add(eventID, eventTime) {
+//        process(event) // first time eventID is seen in the last 10 minutes
+//    }
+//
+// The set's notion of now is the latest time passed to Add or Expire. An ID is remembered for at least the window
+// and at most the window plus one generation after it was first seen; seeing it again does not extend that.
+//
+// NOTE: The Judy arrays allocate memory outside of the Go runtime. It is very important that you call Free() on a
+// WindowSet after using it to prevent memory leaks.
+type WindowSet struct {
+	span int64
+	// gens is a ring of generations indexed by generation number; every array in it belongs to a generation
+	// inside the window, as the others are freed when the window moves.
+	gens    []Judy1
+	current int64
+	started bool
+}
+
+// NewWindowSet returns an empty set over the given window, divided into the given number of generations. More
+// generations expire IDs closer to the window, at the cost of more arrays to search in Add and Contains.
+// It panics if generations is less than 1 or the window is shorter than one nanosecond per generation.
+func NewWindowSet(window time.Duration, generations int) *WindowSet {
+	if generations < 1 || window < time.Duration(generations) {
+		panic("judy: WindowSet needs at least one generation of at least one nanosecond")
+	}
+	return &WindowSet{
+		span: int64(window) / int64(generations),
+		gens: make([]Judy1, generations+1),
+	}
+}
+
+// Add id, seen at time t.
+// Return true if id was not seen in the window before, otherwise false. An ID seen at a time that has already left
+// the window is not added and false is returned, since it may have been seen and expired.
+func (w *WindowSet) Add(id uint64, t time.Time) bool {
+	number := w.generation(t)
+	if !w.advance(number) {
+		return false
+	}
+	if w.Contains(id) {
+		return false
+	}
+	return w.gens[w.slot(number)].Set(id)
+}
+
+// Contains tests if id was seen in the window.
+func (w *WindowSet) Contains(id uint64) bool {
+	for i := range w.gens {
+		if w.gens[i].Test(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expire moves the window up to time t and frees the generations that leave it. Add does this as well; call
+// Expire to release memory when no IDs are being added.
+func (w *WindowSet) Expire(t time.Time) {
+	w.advance(w.generation(t))
+}
+
+// advance moves the window up to generation number if it is ahead of the current one, freeing the generations that
+// leave the window. Return false if number has already left the window.
+func (w *WindowSet) advance(number int64) bool {
+	n := int64(len(w.gens))
+	if !w.started {
+		w.current, w.started = number-n, true
+	}
+	if number <= w.current {
+		return number > w.current-n
+	}
+	for g := max(w.current+1, number-n+1); g <= number; g++ {
+		w.gens[w.slot(g)].Free()
+	}
+	w.current = number
+	return true
+}
+
+func (w *WindowSet) generation(t time.Time) int64 {
+	return t.UnixNano() / w.span
+}
+
+func (w *WindowSet) slot(number int64) int {
+	n := int64(len(w.gens))
+	return int((number%n + n) % n)
+}
+
+// Count the number of IDs in the window.
+func (w *WindowSet) CountAll() uint64 {
+	var n uint64
+	for i := range w.gens {
+		n += w.gens[i].CountAll()
+	}
+	return n
+}
+
+// Return the number of bytes of memory currently in use by every generation of the set.
+func (w *WindowSet) MemoryUsed() uint64 {
+	var mem uint64
+	for i := range w.gens {
+		mem += w.gens[i].MemoryUsed()
+	}
+	return mem
+}
+
+// Free every generation of the set.
+// Return the number of bytes freed.
+func (w *WindowSet) Free() uint64 {
+	var freed uint64
+	for i := range w.gens {
+		freed += w.gens[i].Free()
+	}
+	w.started = false
+	return freed
+}
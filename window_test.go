@@ -0,0 +1,89 @@
+package judy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowSet(t *testing.T) {
+
+	w := NewWindowSet(10*time.Minute, 10)
+	defer w.Free()
+
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if !w.Add(1, t0) || w.Add(1, t0.Add(time.Minute)) {
+		t.Error("Add(1) should only report the first sighting")
+	}
+	if !w.Add(2, t0.Add(5*time.Minute)) || !w.Add(3, t0.Add(9*time.Minute)) {
+		t.Error("Add should report new IDs")
+	}
+	if ct := w.CountAll(); ct != 3 {
+		t.Errorf("CountAll should be 3, was %v", ct)
+	}
+
+	// 1 is remembered for at least the window, and forgotten by the window plus one generation
+	if !w.Add(4, t0.Add(10*time.Minute)) || !w.Contains(1) {
+		t.Error("1 should still be in the window after 10 minutes")
+	}
+	w.Expire(t0.Add(11 * time.Minute))
+	if w.Contains(1) || !w.Contains(2) {
+		t.Error("1 should have expired after 11 minutes, and 2 should not")
+	}
+	if !w.Add(1, t0.Add(11*time.Minute)) {
+		t.Error("1 should be new again after expiring")
+	}
+
+	// an event from before the window is dropped
+	if w.Add(5, t0) {
+		t.Error("An ID seen before the window should not be added")
+	}
+	// an out-of-order event still inside the window is added to its own generation
+	if !w.Add(6, t0.Add(3*time.Minute)) || !w.Contains(6) {
+		t.Error("An out-of-order ID inside the window should be added")
+	}
+	w.Expire(t0.Add(14 * time.Minute))
+	if w.Contains(6) {
+		t.Error("The out-of-order ID should expire with its generation")
+	}
+
+	if w.MemoryUsed() == 0 {
+		t.Error("MemoryUsed should not be 0")
+	}
+
+	// a jump far ahead expires everything
+	w.Expire(t0.Add(time.Hour))
+	if ct := w.CountAll(); ct != 0 {
+		t.Errorf("Every generation should have expired, CountAll was %v", ct)
+	}
+	if mem := w.MemoryUsed(); mem != 0 {
+		t.Errorf("Expired generations should be freed, MemoryUsed was %v", mem)
+	}
+}
+
+func TestWindowSetFree(t *testing.T) {
+
+	w := NewWindowSet(time.Second, 1)
+	t0 := time.Unix(1000, 0)
+
+	for id := uint64(0); id < 100; id++ {
+		w.Add(id, t0)
+	}
+	if r := w.Free(); r == 0 {
+		t.Error("Free should return the bytes freed")
+	}
+	if !w.Add(0, t0) {
+		t.Error("A freed set should be empty and usable")
+	}
+	w.Free()
+}
+
+func TestNewWindowSetPanics(t *testing.T) {
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewWindowSet with 0 generations should panic")
+		}
+	}()
+	NewWindowSet(time.Minute, 0)
+}